@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+)
+
+func chatboxCommand(args []string) error {
+	if len(args) < 1 || args[0] != "preview" {
+		return fmt.Errorf("usage: chatbox preview [-config file]")
+	}
+
+	flags, configPath := newFlagSet("chatbox preview")
+	flags.Parse(args[1:])
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	builder, err := newChatBox(config)
+	if err != nil {
+		return err
+	}
+
+	// fill in the example values of every referenced placeholder, regardless of active modules
+	registry := newPlaceholderRegistry(config, createModules(config))
+
+	builder.BeginTick()
+	for _, name := range builder.ReferencedPlaceholders() {
+		if placeholder, ok := registry.Find(name); ok {
+			builder.Placeholder(name, placeholder.Example)
+		}
+	}
+
+	pages := builder.Pages()
+	for i, page := range pages {
+		if len(pages) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("--- %s ---\n", page.Name())
+		}
+		fmt.Println(builder.RenderPage(page))
+	}
+	return nil
+}
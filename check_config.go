@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Glowman554/OpenOSC/config"
+)
+
+func checkConfigCommand(args []string) error {
+	flags, configPath := newFlagSet("check-config")
+	flags.Parse(args)
+
+	// unlike run this must not touch the file, so pending migrations are only reported
+	cfg, pending, err := config.ReadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	for _, change := range pending {
+		fmt.Printf("pending migration: %s\n", change)
+	}
+
+	err = checkConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	fmt.Printf("%s is valid\n", *configPath)
+	return nil
+}
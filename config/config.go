@@ -2,9 +2,16 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
 type LeashConfig struct {
@@ -37,11 +44,189 @@ type OpenShockControlConfig struct {
 type GpuInfoConfig struct {
 	EnableAmd    bool `json:"enableAmd"`
 	EnableNvidia bool `json:"enableNvidia"`
+	// EnableSysfs reads the GPUs from the kernel without vendor tools, published as gpuinfo.drm*.
+	// Cards already reported by rocm-smi or nvidia-smi are not included.
+	EnableSysfs bool `json:"enableSysfs"`
+	// SysfsRoot is where sysfs is mounted.
+	SysfsRoot string `json:"sysfsRoot"`
+}
+
+type LocaleConfig struct {
+	Language string `json:"language"`
+	// Translations override or extend the built-in translations of fixed strings.
+	Translations map[string]string `json:"translations"`
+}
+
+type MediaConfig struct {
+	// ProgressBarStyle is one of classic, blocks, emoji, line or percent.
+	ProgressBarStyle string `json:"progressBarStyle"`
+	ProgressBarWidth int    `json:"progressBarWidth"`
+	// PreferredPlayers decides which player is shown and controlled if several are playing, first
+	// match wins. Players are matched by bus name without org.mpris.MediaPlayer2. (e.g. "spotify")
+	// or their identity (e.g. "Spotify").
+	PreferredPlayers []string `json:"preferredPlayers"`
+	// IgnoredPlayers are never shown or controlled.
+	IgnoredPlayers []string `json:"ignoredPlayers"`
+	// PlayerPolicy decides between players that are not preferred, recent picks the one that most
+	// recently started playing and first the one that appeared first.
+	PlayerPolicy string `json:"playerPolicy"`
+}
+
+type MediaHistoryConfig struct {
+	// HistorySize is the number of tracks kept for the media.previous placeholders and the TUI.
+	HistorySize int `json:"historySize"`
+	// Tracks listened to for less than MinimumListenSeconds are skipped.
+	MinimumListenSeconds int `json:"minimumListenSeconds"`
+	// LogPath is a JSONL file every played track is appended to, empty disables the log.
+	LogPath string `json:"logPath"`
+}
+
+type MediaArtConfig struct {
+	// Address the cover art server listens on, keep it on localhost unless other machines need it.
+	Address string `json:"address"`
+	// PublicHost is the host put into the media.art URLs, by default the listen address or
+	// 127.0.0.1 when listening on all interfaces.
+	PublicHost string `json:"publicHost"`
+	// AllowedOrigin is sent as Access-Control-Allow-Origin on /current so overlay pages from
+	// another origin can read the current track. Empty allows no other origins.
+	AllowedOrigin string `json:"allowedOrigin"`
+}
+
+type NotificationsConfig struct {
+	// AllowedApps are the only apps whose notifications are shown, matched case insensitively
+	// against the app name (e.g. "discord"). Nothing is shown while it is empty.
+	AllowedApps []string `json:"allowedApps"`
+	// Redact are regular expressions, matches in the summary or body are replaced by "***".
+	Redact []string `json:"redact"`
+	// DisplaySeconds is how long the notification placeholders are kept.
+	DisplaySeconds int `json:"displaySeconds"`
+	// Message is shown as a timed chatbox message for every notification if set, e.g.
+	// "🔔 {notification.app}: {notification.summary}".
+	Message string `json:"message"`
+}
+
+type ProcessInfoConfig struct {
+	// Processes maps placeholder names to executable names, "vrchat": "VRChat.exe" is published as
+	// process.vrchat.cpu and so on. "self" is OpenOSC itself.
+	Processes map[string]string `json:"processes"`
+	// MemoryProcess is the process whose memory pressure is sent as the
+	// VRCOSC/Process/MemoryPressure float parameter, empty disables it.
+	MemoryProcess string `json:"memoryProcess"`
+	// MemoryLimitMB is the memory usage at which the pressure is 1, 0 uses the total memory.
+	MemoryLimitMB int `json:"memoryLimitMB"`
+}
+
+type SysInfoConfig struct {
+	// TimeZones are published as sysinfo.time.tz.<zone>, e.g. sysinfo.time.tz.Asia/Tokyo.
+	TimeZones []string `json:"timeZones"`
+	// Collectors are the enabled metrics: cpu, cores, temperature, memory, network, disk, diskio,
+	// battery, load, uptime and processes.
+	Collectors []string `json:"collectors"`
+	// DiskPaths maps names to mount points, "root": "/" is published as sysinfo.disk.root.
+	DiskPaths map[string]string `json:"diskPaths"`
+	// NetworkInterfaces are summed up for the network collector, empty uses all but loopback.
+	NetworkInterfaces []string `json:"networkInterfaces"`
+	// TemperatureSensor is part of the sensor key for the CPU temperature, empty picks one.
+	TemperatureSensor string `json:"temperatureSensor"`
+	// TemperatureUnit is celsius or fahrenheit.
+	TemperatureUnit string `json:"temperatureUnit"`
+	// ByteUnit is iec (KiB, MiB, ...) or si (kB, MB, ...).
+	ByteUnit string `json:"byteUnit"`
+	// NetworkUnit is bytes (MiB/s) or bits (Mbit/s).
+	NetworkUnit string `json:"networkUnit"`
+	// Precision is the number of decimals of sizes, rates, temperatures and the load average.
+	Precision int `json:"precision"`
+	// IntervalSeconds overrides how often a collector is read, e.g. "disk": 60.
+	IntervalSeconds map[string]int `json:"intervalSeconds"`
+}
+
+// ChatboxLine is stored as a plain string unless it has a priority.
+type ChatboxLine struct {
+	Text     string `json:"text"`
+	Priority int    `json:"priority,omitempty"`
+}
+
+func (l *ChatboxLine) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = ChatboxLine{Text: text}
+		return nil
+	}
+
+	type plain ChatboxLine
+	return json.Unmarshal(data, (*plain)(l))
+}
+
+func (l ChatboxLine) MarshalJSON() ([]byte, error) {
+	if l.Priority == 0 {
+		return json.Marshal(l.Text)
+	}
+
+	type plain ChatboxLine
+	return json.Marshal(plain(l))
+}
+
+// ChatboxFlags override the immediate / notify flags of chatboxConfig if set.
+type ChatboxFlags struct {
+	Immediate *bool `json:"immediate,omitempty"`
+	Notify    *bool `json:"notify,omitempty"`
+}
+
+type ChatboxPage struct {
+	Name            string        `json:"name"`
+	DurationSeconds int           `json:"durationSeconds"`
+	Lines           []ChatboxLine `json:"lines"`
+	ChatboxFlags
+}
+
+type ChatboxScheduledMessage struct {
+	Text            string `json:"text"`
+	IntervalSeconds int    `json:"intervalSeconds"`
+	DurationSeconds int    `json:"durationSeconds"`
+	ChatboxFlags
+}
+
+// OutputConfig is an additional target for the chatbox text next to VRChat.
+type OutputConfig struct {
+	// Type is file or obs.
+	Type string `json:"type"`
+	// Lines are rendered instead of the chatbox text if set, e.g. only "{media.title}".
+	Lines []ChatboxLine `json:"lines,omitempty"`
+	// Path of the text file for file outputs.
+	Path string `json:"path,omitempty"`
+	// Address, Password and Source (the name of the text source) for obs outputs.
+	Address  string `json:"address,omitempty"`
+	Password string `json:"password,omitempty"`
+	Source   string `json:"source,omitempty"`
+}
+
+type ChatboxConfig struct {
+	MaxCharacters int  `json:"maxCharacters"`
+	MaxLines      int  `json:"maxLines"`
+	Paginate      bool `json:"paginate"`
+	// Immediate sends the text right away instead of opening the keyboard, Notify plays the chatbox sound.
+	Immediate           bool `json:"immediate"`
+	Notify              bool `json:"notify"`
+	KeepAliveSeconds    int  `json:"keepAliveSeconds"`
+	RateLimitBurst      int  `json:"rateLimitBurst"`
+	RateLimitIntervalMS int  `json:"rateLimitIntervalMS"`
+	// TypingTransitionMS shows the typing indicator this long before switching pages, 0 disables it.
+	TypingTransitionMS int `json:"typingTransitionMS"`
+	// UpdateIntervalMS re-renders the chatbox between module ticks so marquees and progress bars
+	// move smoothly, 0 only renders after every module tick.
+	UpdateIntervalMS int `json:"updateIntervalMS"`
+	// Pages replace the top level chatbox lines if set.
+	Pages             []ChatboxPage             `json:"pages"`
+	ScheduledMessages []ChatboxScheduledMessage `json:"scheduledMessages"`
 }
 
 type Config struct {
-	Chatbox                []string               `json:"chatbox"`
-	ChatboxDebug           bool                   `json:"chatboxDebug"`
+	Chatbox       []ChatboxLine  `json:"chatbox"`
+	ChatboxConfig ChatboxConfig  `json:"chatboxConfig"`
+	Outputs       []OutputConfig `json:"outputs"`
+	TUI           bool           `json:"tui"`
+	// DBusControl publishes app.openosc.Control on the session bus for desktop tools.
+	DBusControl            bool                   `json:"dbusControl"`
 	SendIP                 string                 `json:"sendIP"`
 	SendPort               int                    `json:"sendPort"`
 	ReceivePort            int                    `json:"receivePort"`
@@ -50,19 +235,42 @@ type Config struct {
 	OpenShockConfig        OpenShockConfig        `json:"openShockConfig"`
 	OpenShockControlConfig OpenShockControlConfig `json:"openShockControlConfig"`
 	GpuInfo                GpuInfoConfig          `json:"gpuInfo"`
+	Media                  MediaConfig            `json:"media"`
+	MediaHistory           MediaHistoryConfig     `json:"mediaHistory"`
+	MediaArt               MediaArtConfig         `json:"mediaArt"`
+	Notifications          NotificationsConfig    `json:"notifications"`
+	SysInfo                SysInfoConfig          `json:"sysInfo"`
+	ProcessInfo            ProcessInfoConfig      `json:"processInfo"`
+	Locale                 LocaleConfig           `json:"locale"`
 }
 
 var defaultConfig = Config{
-	Chatbox: []string{
-		"🎵 {media.title} - {media.artist}",
-		"{media.progress}",
-		"CPU: {sysinfo.cpu}, Memory: {sysinfo.memory}",
-		"{sysinfo.time.12h} / {sysinfo.time.24h}",
+	Chatbox: []ChatboxLine{
+		{Text: "🎵 {media.title} - {media.artist}", Priority: 1},
+		{Text: "{media.progress}"},
+		{Text: "CPU: {sysinfo.cpu}, Memory: {sysinfo.memory}"},
+		{Text: "{sysinfo.time.12h} / {sysinfo.time.24h}", Priority: 2},
 	},
-	ChatboxDebug: false,
-	SendIP:       "127.0.0.1",
-	SendPort:     9000,
-	ReceivePort:  9001,
+	ChatboxConfig: ChatboxConfig{
+		MaxCharacters:       144,
+		MaxLines:            9,
+		Paginate:            false,
+		Immediate:           true,
+		Notify:              false,
+		KeepAliveSeconds:    20,
+		RateLimitBurst:      3,
+		RateLimitIntervalMS: 1500,
+		TypingTransitionMS:  2000,
+		UpdateIntervalMS:    1000,
+		Pages:               []ChatboxPage{},
+		ScheduledMessages:   []ChatboxScheduledMessage{},
+	},
+	Outputs:     []OutputConfig{},
+	TUI:         false,
+	DBusControl: false,
+	SendIP:      "127.0.0.1",
+	SendPort:    9000,
+	ReceivePort: 9001,
 	ActiveModules: []string{
 		"media_chatbox",
 		"media_control",
@@ -96,6 +304,56 @@ var defaultConfig = Config{
 	GpuInfo: GpuInfoConfig{
 		EnableAmd:    true,
 		EnableNvidia: true,
+		EnableSysfs:  true,
+		SysfsRoot:    "/sys",
+	},
+	Media: MediaConfig{
+		ProgressBarStyle: "classic",
+		ProgressBarWidth: 15,
+		PreferredPlayers: []string{},
+		IgnoredPlayers:   []string{},
+		PlayerPolicy:     "recent",
+	},
+	MediaHistory: MediaHistoryConfig{
+		HistorySize:          10,
+		MinimumListenSeconds: 30,
+		LogPath:              "media_history.jsonl",
+	},
+	MediaArt: MediaArtConfig{
+		Address:       "127.0.0.1:9557",
+		PublicHost:    "",
+		AllowedOrigin: "",
+	},
+	Notifications: NotificationsConfig{
+		AllowedApps:    []string{},
+		Redact:         []string{},
+		DisplaySeconds: 10,
+		Message:        "",
+	},
+	SysInfo: SysInfoConfig{
+		TimeZones:         []string{},
+		Collectors:        []string{"cpu", "memory"},
+		DiskPaths:         map[string]string{"root": "/"},
+		NetworkInterfaces: []string{},
+		TemperatureSensor: "",
+		TemperatureUnit:   "celsius",
+		ByteUnit:          "iec",
+		NetworkUnit:       "bytes",
+		Precision:         1,
+		IntervalSeconds:   map[string]int{},
+	},
+	ProcessInfo: ProcessInfoConfig{
+		Processes: map[string]string{
+			"vrchat":   "VRChat.exe",
+			"vrserver": "vrserver",
+			"openosc":  "self",
+		},
+		MemoryProcess: "vrchat",
+		MemoryLimitMB: 0,
+	},
+	Locale: LocaleConfig{
+		Language:     "en",
+		Translations: map[string]string{},
 	},
 }
 
@@ -147,13 +405,39 @@ func updateConfigFile(filename string) error {
 		return err
 	}
 
-	var config map[string]any
-	err = json.Unmarshal(data, &config)
+	config, changed, err := migrateConfig(data)
 	if err != nil {
 		log.Printf("failed to unmarshal config: %v", err)
 		return err
 	}
 
+	if changed {
+		log.Println("Writing updated config")
+		configJson, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			log.Printf("failed to marshal config: %v", err)
+			return err
+		}
+
+		err = os.WriteFile(filename, configJson, 0)
+		if err != nil {
+			log.Printf("failed to write config file: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateConfig adds missing options to the config and replaces old ones, it reports whether
+// anything changed.
+func migrateConfig(data []byte) (map[string]any, bool, error) {
+	var config map[string]any
+	err := json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, false, err
+	}
+
 	changed := false
 
 	// add leash config
@@ -191,10 +475,41 @@ func updateConfigFile(filename string) error {
 		config["openShockControlConfig"] = defaultConfig.OpenShockControlConfig
 	}
 
-	// add chatboxDebug
-	if _, ok := config["chatboxDebug"]; !ok {
+	// replace chatboxDebug with tui
+	if val, ok := config["chatboxDebug"]; ok {
+		changed = true
+		enabled, _ := val.(bool)
+		config["tui"] = enabled
+		delete(config, "chatboxDebug")
+	}
+
+	// add chatboxConfig
+	if _, ok := config["chatboxConfig"]; !ok {
+		changed = true
+		config["chatboxConfig"] = defaultConfig.ChatboxConfig
+	}
+
+	// add new chatboxConfig options
+	if chatboxConfig, ok := config["chatboxConfig"].(map[string]any); ok {
+		changed = addMissingKeys(chatboxConfig, defaultConfig.ChatboxConfig) || changed
+	}
+
+	// add outputs
+	if _, ok := config["outputs"]; !ok {
 		changed = true
-		config["chatboxDebug"] = defaultConfig.ChatboxDebug
+		config["outputs"] = defaultConfig.Outputs
+	}
+
+	// add tui
+	if _, ok := config["tui"]; !ok {
+		changed = true
+		config["tui"] = defaultConfig.TUI
+	}
+
+	// add dbusControl
+	if _, ok := config["dbusControl"]; !ok {
+		changed = true
+		config["dbusControl"] = defaultConfig.DBusControl
 	}
 
 	// add gpuInfo
@@ -203,20 +518,333 @@ func updateConfigFile(filename string) error {
 		config["gpuInfo"] = defaultConfig.GpuInfo
 	}
 
-	if changed {
-		log.Println("Writing updated config")
-		configJson, err := json.MarshalIndent(config, "", "  ")
-		if err != nil {
-			log.Printf("failed to marshal config: %v", err)
-			return err
+	// add new gpuInfo options
+	if gpuInfo, ok := config["gpuInfo"].(map[string]any); ok {
+		changed = addMissingKeys(gpuInfo, defaultConfig.GpuInfo) || changed
+	}
+
+	// add media
+	if _, ok := config["media"]; !ok {
+		changed = true
+		config["media"] = defaultConfig.Media
+	}
+
+	// add new media options
+	if media, ok := config["media"].(map[string]any); ok {
+		changed = addMissingKeys(media, defaultConfig.Media) || changed
+	}
+
+	// add mediaHistory
+	if _, ok := config["mediaHistory"]; !ok {
+		changed = true
+		config["mediaHistory"] = defaultConfig.MediaHistory
+	}
+
+	// add mediaArt
+	if _, ok := config["mediaArt"]; !ok {
+		changed = true
+		config["mediaArt"] = defaultConfig.MediaArt
+	}
+
+	// add new mediaArt options
+	if mediaArt, ok := config["mediaArt"].(map[string]any); ok {
+		changed = addMissingKeys(mediaArt, defaultConfig.MediaArt) || changed
+	}
+
+	// add notifications
+	if _, ok := config["notifications"]; !ok {
+		changed = true
+		config["notifications"] = defaultConfig.Notifications
+	}
+
+	// add sysInfo
+	if _, ok := config["sysInfo"]; !ok {
+		changed = true
+		config["sysInfo"] = defaultConfig.SysInfo
+	}
+
+	// add new sysInfo options
+	if sysInfo, ok := config["sysInfo"].(map[string]any); ok {
+		changed = addMissingKeys(sysInfo, defaultConfig.SysInfo) || changed
+	}
+
+	// add processInfo
+	if _, ok := config["processInfo"]; !ok {
+		changed = true
+		config["processInfo"] = defaultConfig.ProcessInfo
+	}
+
+	// add locale
+	if _, ok := config["locale"]; !ok {
+		changed = true
+		config["locale"] = defaultConfig.Locale
+	}
+
+	return config, changed, nil
+}
+
+// ReadConfig reads the config like LoadConfig but never writes the file. The migrations LoadConfig
+// would write are applied in memory and returned as a list of the keys they add, remove or change.
+func ReadConfig(filename string) (*Config, []string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var original map[string]any
+	err = json.Unmarshal(data, &original)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	migrated, _, err := migrateConfig(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the migrations insert structs, a round trip turns them into maps like the original
+	migratedData, err := json.Marshal(migrated)
+	if err != nil {
+		return nil, nil, err
+	}
+	var normalized map[string]any
+	err = json.Unmarshal(migratedData, &normalized)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var config Config
+	err = json.Unmarshal(migratedData, &config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pending := configChanges("", original, normalized)
+	sort.Strings(pending)
+	return &config, pending, nil
+}
+
+// configChanges describes the keys that differ between two versions of a config section.
+func configChanges(prefix string, before map[string]any, after map[string]any) []string {
+	changes := []string{}
+	for key, value := range after {
+		previous, ok := before[key]
+		if !ok {
+			changes = append(changes, "add "+prefix+key)
+			continue
 		}
 
-		err = os.WriteFile(filename, configJson, 0)
-		if err != nil {
-			log.Printf("failed to write config file: %v", err)
-			return err
+		previousSection, previousOk := previous.(map[string]any)
+		section, sectionOk := value.(map[string]any)
+		if previousOk && sectionOk {
+			changes = append(changes, configChanges(prefix+key+".", previousSection, section)...)
+		} else if !reflect.DeepEqual(previous, value) {
+			changes = append(changes, "change "+prefix+key)
 		}
 	}
 
-	return nil
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changes = append(changes, "remove "+prefix+key)
+		}
+	}
+	return changes
+}
+
+// addMissingKeys adds every top level key of defaults that is missing in section.
+func addMissingKeys(section map[string]any, defaults any) bool {
+	data, err := json.Marshal(defaults)
+	if err != nil {
+		return false
+	}
+
+	var defaultSection map[string]any
+	err = json.Unmarshal(data, &defaultSection)
+	if err != nil {
+		return false
+	}
+
+	changed := false
+	for key, value := range defaultSection {
+		if _, ok := section[key]; !ok {
+			changed = true
+			section[key] = value
+		}
+	}
+	return changed
+}
+
+// Validate checks the plain values of the config. Names defined by the feature packages (progress
+// styles, player policies, collectors, languages and templates) are checked by validateConfig in
+// the main package so config does not depend on them.
+func (c *Config) Validate() error {
+	errs := []error{}
+
+	if c.SendIP == "" {
+		errs = append(errs, fmt.Errorf("sendIP must not be empty"))
+	}
+
+	if c.SendPort <= 0 || c.SendPort > 65535 {
+		errs = append(errs, fmt.Errorf("sendPort %d is out of range", c.SendPort))
+	}
+
+	if c.ReceivePort <= 0 || c.ReceivePort > 65535 {
+		errs = append(errs, fmt.Errorf("receivePort %d is out of range", c.ReceivePort))
+	}
+
+	if c.ChatboxConfig.MaxCharacters <= 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.maxCharacters must be positive"))
+	}
+
+	if c.ChatboxConfig.MaxLines <= 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.maxLines must be positive"))
+	}
+
+	if c.ChatboxConfig.KeepAliveSeconds < 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.keepAliveSeconds must not be negative"))
+	}
+
+	if c.ChatboxConfig.TypingTransitionMS < 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.typingTransitionMS must not be negative"))
+	}
+
+	if c.ChatboxConfig.UpdateIntervalMS < 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.updateIntervalMS must not be negative"))
+	}
+
+	if c.ChatboxConfig.RateLimitBurst <= 0 || c.ChatboxConfig.RateLimitIntervalMS <= 0 {
+		errs = append(errs, fmt.Errorf("chatboxConfig.rateLimitBurst and rateLimitIntervalMS must be positive"))
+	}
+
+	for i, page := range c.ChatboxConfig.Pages {
+		if len(page.Lines) == 0 {
+			errs = append(errs, fmt.Errorf("chatboxConfig.pages[%d] has no lines", i))
+		}
+		if page.DurationSeconds < 0 {
+			errs = append(errs, fmt.Errorf("chatboxConfig.pages[%d].durationSeconds must not be negative", i))
+		}
+	}
+
+	for i, message := range c.ChatboxConfig.ScheduledMessages {
+		if message.IntervalSeconds <= 0 {
+			errs = append(errs, fmt.Errorf("chatboxConfig.scheduledMessages[%d].intervalSeconds must be positive", i))
+		}
+		if message.DurationSeconds <= 0 {
+			errs = append(errs, fmt.Errorf("chatboxConfig.scheduledMessages[%d].durationSeconds must be positive", i))
+		}
+	}
+
+	for i, output := range c.Outputs {
+		switch output.Type {
+		case "file":
+			if output.Path == "" {
+				errs = append(errs, fmt.Errorf("outputs[%d].path must not be empty", i))
+			}
+		case "obs":
+			if !strings.HasPrefix(output.Address, "ws://") && !strings.HasPrefix(output.Address, "wss://") {
+				errs = append(errs, fmt.Errorf("outputs[%d].address %q must be a ws:// or wss:// URL", i, output.Address))
+			}
+			if output.Source == "" {
+				errs = append(errs, fmt.Errorf("outputs[%d].source must not be empty", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("outputs[%d].type %q is not supported (available: file, obs)", i, output.Type))
+		}
+	}
+
+	if c.Media.ProgressBarWidth <= 0 {
+		errs = append(errs, fmt.Errorf("media.progressBarWidth must be positive"))
+	}
+
+	if c.MediaHistory.HistorySize <= 0 {
+		errs = append(errs, fmt.Errorf("mediaHistory.historySize must be positive"))
+	}
+
+	if c.MediaHistory.MinimumListenSeconds < 0 {
+		errs = append(errs, fmt.Errorf("mediaHistory.minimumListenSeconds must not be negative"))
+	}
+
+	if _, _, err := net.SplitHostPort(c.MediaArt.Address); err != nil {
+		errs = append(errs, fmt.Errorf("mediaArt.address: %w", err))
+	}
+
+	for _, pattern := range c.Notifications.Redact {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("notifications.redact: %w", err))
+		}
+	}
+
+	if c.Notifications.DisplaySeconds <= 0 {
+		errs = append(errs, fmt.Errorf("notifications.displaySeconds must be positive"))
+	}
+
+	for _, zone := range c.SysInfo.TimeZones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			errs = append(errs, fmt.Errorf("sysInfo.timeZones: %w", err))
+		}
+	}
+
+	for collector, seconds := range c.SysInfo.IntervalSeconds {
+		if seconds <= 0 {
+			errs = append(errs, fmt.Errorf("sysInfo.intervalSeconds.%s must be positive", collector))
+		}
+	}
+
+	for name := range c.SysInfo.DiskPaths {
+		// read and write are taken by the diskio collector
+		if name == "" || name == "read" || name == "write" || strings.ContainsAny(name, ". {}|") {
+			errs = append(errs, fmt.Errorf("sysInfo.diskPaths: %q is not a valid placeholder name", name))
+		}
+	}
+
+	if c.SysInfo.TemperatureUnit != "celsius" && c.SysInfo.TemperatureUnit != "fahrenheit" {
+		errs = append(errs, fmt.Errorf("sysInfo.temperatureUnit must be celsius or fahrenheit"))
+	}
+
+	if c.SysInfo.ByteUnit != "iec" && c.SysInfo.ByteUnit != "si" {
+		errs = append(errs, fmt.Errorf("sysInfo.byteUnit must be iec or si"))
+	}
+
+	if c.SysInfo.NetworkUnit != "bytes" && c.SysInfo.NetworkUnit != "bits" {
+		errs = append(errs, fmt.Errorf("sysInfo.networkUnit must be bytes or bits"))
+	}
+
+	if c.SysInfo.Precision < 0 || c.SysInfo.Precision > 3 {
+		errs = append(errs, fmt.Errorf("sysInfo.precision %d is out of range (0-3)", c.SysInfo.Precision))
+	}
+
+	for name, executable := range c.ProcessInfo.Processes {
+		if name == "" || strings.ContainsAny(name, ". {}|") {
+			errs = append(errs, fmt.Errorf("processInfo.processes: %q is not a valid placeholder name", name))
+		}
+		if executable == "" {
+			errs = append(errs, fmt.Errorf("processInfo.processes.%s has no executable", name))
+		}
+	}
+
+	if _, ok := c.ProcessInfo.Processes[c.ProcessInfo.MemoryProcess]; c.ProcessInfo.MemoryProcess != "" && !ok {
+		errs = append(errs, fmt.Errorf("processInfo.memoryProcess %q is not in processInfo.processes", c.ProcessInfo.MemoryProcess))
+	}
+
+	if c.ProcessInfo.MemoryLimitMB < 0 {
+		errs = append(errs, fmt.Errorf("processInfo.memoryLimitMB must not be negative"))
+	}
+
+	if c.OpenShockConfig.MaximumIntensity < 0 || c.OpenShockConfig.MaximumIntensity > 100 {
+		errs = append(errs, fmt.Errorf("openShockConfig.maximumIntensity %d is out of range (0-100)", c.OpenShockConfig.MaximumIntensity))
+	}
+
+	if c.OpenShockConfig.MaximumDurationMS < 0 || c.OpenShockConfig.MaximumDurationMS > 30000 {
+		errs = append(errs, fmt.Errorf("openShockConfig.maximumDurationMS %d is out of range (0-30000)", c.OpenShockConfig.MaximumDurationMS))
+	}
+
+	if c.OpenShockControlConfig.MaximumIntensity < 0 || c.OpenShockControlConfig.MaximumIntensity > 100 {
+		errs = append(errs, fmt.Errorf("openShockControlConfig.maximumIntensity %d is out of range (0-100)", c.OpenShockControlConfig.MaximumIntensity))
+	}
+
+	if c.OpenShockControlConfig.MaximumDurationMS < 0 || c.OpenShockControlConfig.MaximumDurationMS > 30000 {
+		errs = append(errs, fmt.Errorf("openShockControlConfig.maximumDurationMS %d is out of range (0-30000)", c.OpenShockControlConfig.MaximumDurationMS))
+	}
+
+	return errors.Join(errs...)
 }
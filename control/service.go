@@ -0,0 +1,232 @@
+package control
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/oscmod"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/hypebeast/go-osc/osc"
+)
+
+const (
+	ServiceName = "app.openosc.Control"
+	ObjectPath  = dbus.ObjectPath("/app/openosc/Control")
+	Interface   = "app.openosc.Control"
+
+	// PlaceholderPrefix is the namespace of placeholders set over D-Bus, so tools can not spoof
+	// the values of modules.
+	PlaceholderPrefix = "control."
+)
+
+const parameterPrefix = "/avatar/parameters/"
+
+// maxQueuedMessages limits the messages waiting in the chatbox, SendMessage fails beyond it.
+const maxQueuedMessages = 16
+
+// ModuleInfo is returned by ListModules, its D-Bus signature is (ssb).
+type ModuleInfo struct {
+	Id      string
+	Name    string
+	Enabled bool
+}
+
+// Service lets desktop tools control OpenOSC over the session bus, e.g.
+//
+//	busctl --user call app.openosc.Control /app/openosc/Control app.openosc.Control SendMessage su "brb" 10
+type Service struct {
+	conn    *dbus.Conn
+	manager *oscmod.ModuleManager
+	chatbox *chatbox.ChatBoxBuilder
+
+	// parameters are the last values of the avatar parameters, signals are only sent on changes.
+	parameters map[string]any
+	mutex      sync.Mutex
+}
+
+func NewService(manager *oscmod.ModuleManager, chatbox *chatbox.ChatBoxBuilder) *Service {
+	return &Service{
+		manager:    manager,
+		chatbox:    chatbox,
+		parameters: map[string]any{},
+	}
+}
+
+// Placeholders returns the placeholders tools can set.
+func Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{
+		{Name: PlaceholderPrefix + "*", Description: "Set by desktop tools through SetPlaceholder", Example: "Streaming"},
+	}
+}
+
+// Start exports the service and forwards avatar parameter changes received by the manager as
+// signals.
+func (s *Service) Start() error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		log.Printf("Failed to connect to session bus: %v", err)
+		return err
+	}
+	s.conn = conn
+
+	err = conn.Export((*object)(s), ObjectPath, Interface)
+	if err != nil {
+		return err
+	}
+
+	node := &introspect.Node{
+		Name: string(ObjectPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			{
+				Name:    Interface,
+				Methods: introspect.Methods((*object)(s)),
+				Signals: []introspect.Signal{
+					{
+						Name: "ParameterChanged",
+						Args: []introspect.Arg{
+							{Name: "name", Type: "s"},
+							{Name: "value", Type: "v"},
+						},
+					},
+				},
+			},
+		},
+	}
+	err = conn.Export(introspect.NewIntrospectable(node), ObjectPath, "org.freedesktop.DBus.Introspectable")
+	if err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(ServiceName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		log.Printf("Failed to request %s: %v", ServiceName, err)
+		return err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("%s is already taken, is another OpenOSC running?", ServiceName)
+	}
+
+	s.manager.AddDefaultHandler(s.handleMessage)
+	return nil
+}
+
+func (s *Service) handleMessage(msg *osc.Message) {
+	if !strings.HasPrefix(msg.Address, parameterPrefix) || len(msg.Arguments) == 0 {
+		return
+	}
+
+	var value any
+	switch v := msg.Arguments[0].(type) {
+	case float32:
+		// D-Bus has no single precision floats
+		value = float64(v)
+	case int32, bool, string:
+		value = v
+	default:
+		return
+	}
+
+	name := strings.TrimPrefix(msg.Address, parameterPrefix)
+
+	s.mutex.Lock()
+	previous, ok := s.parameters[name]
+	s.parameters[name] = value
+	s.mutex.Unlock()
+
+	if ok && previous == value {
+		return
+	}
+
+	err := s.conn.Emit(ObjectPath, Interface+".ParameterChanged", name, dbus.MakeVariant(value))
+	if err != nil {
+		log.Printf("Failed to emit ParameterChanged: %v", err)
+	}
+}
+
+// object has the methods exported on the bus, the Go-only methods of Service stay private.
+type object Service
+
+// ListModules returns the initialized modules.
+func (s *object) ListModules() ([]ModuleInfo, *dbus.Error) {
+	modules := []ModuleInfo{}
+	for _, status := range s.manager.Statuses() {
+		modules = append(modules, ModuleInfo{Id: status.Id, Name: status.Name, Enabled: status.Enabled})
+	}
+	return modules, nil
+}
+
+func (s *object) SetModuleEnabled(id string, enabled bool) *dbus.Error {
+	err := s.manager.SetEnabled(id, enabled)
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// SetPlaceholder sets a control.* placeholder, it is removed after ttl seconds or kept until
+// RemovePlaceholder if ttl is 0.
+func (s *object) SetPlaceholder(name string, text string, ttl uint32) *dbus.Error {
+	if !strings.HasPrefix(name, PlaceholderPrefix) {
+		return dbus.MakeFailedError(fmt.Errorf("placeholder %s has to start with %s", name, PlaceholderPrefix))
+	}
+
+	s.chatbox.StickyPlaceholder(name, text, time.Duration(ttl)*time.Second)
+	return nil
+}
+
+func (s *object) RemovePlaceholder(name string) *dbus.Error {
+	if !strings.HasPrefix(name, PlaceholderPrefix) {
+		return dbus.MakeFailedError(fmt.Errorf("placeholder %s has to start with %s", name, PlaceholderPrefix))
+	}
+
+	s.chatbox.RemovePlaceholder(name)
+	return nil
+}
+
+// SendMessage shows a one-shot chatbox message for duration seconds, text is a template.
+func (s *object) SendMessage(text string, duration uint32) *dbus.Error {
+	if duration == 0 {
+		return dbus.MakeFailedError(fmt.Errorf("duration has to be at least 1 second"))
+	}
+	if s.chatbox.QueuedMessages() >= maxQueuedMessages {
+		return dbus.MakeFailedError(fmt.Errorf("%d messages are already queued", maxQueuedMessages))
+	}
+
+	err := s.chatbox.Message(text, time.Duration(duration)*time.Second, nil)
+	if err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	return nil
+}
+
+// Clear empties the chatbox until its content changes.
+func (s *object) Clear() *dbus.Error {
+	s.chatbox.Clear()
+	return nil
+}
+
+// SetTyping shows the typing indicator while a tool is busy, e.g. while it prepares a message.
+func (s *object) SetTyping(typing bool) *dbus.Error {
+	if typing {
+		s.chatbox.BeginTyping("control")
+	} else {
+		s.chatbox.EndTyping("control")
+	}
+	return nil
+}
+
+// EmergencyStop stops (or releases) leash movement and OpenShock actions.
+func (s *object) EmergencyStop(stop bool) *dbus.Error {
+	oscmod.EmergencyStop(stop)
+	return nil
+}
+
+func (s *object) EmergencyStopped() (bool, *dbus.Error) {
+	return oscmod.IsEmergencyStopped(), nil
+}
@@ -5,8 +5,17 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// bracketRe extracts the marketing name, e.g. "Navi 31 [Radeon RX 7900 XTX]".
+var bracketRe = regexp.MustCompile(`\[(.*?)\]`)
+
+// numberRe finds the value in fields like "(2482Mhz)".
+var numberRe = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+
 type AMDProvider struct {
 }
 
@@ -15,25 +24,40 @@ func NewAMDProvider() *AMDProvider {
 }
 
 func (p *AMDProvider) Read() ([]GPUUsage, error) {
-	cmd := exec.Command("rocm-smi", "--showuse", "--showmeminfo", "vram", "--showproductname", "--json")
+	cmd := exec.Command("rocm-smi",
+		"--showuse", "--showmeminfo", "vram", "--showproductname",
+		"--showtemp", "--showpower", "--showmaxpower", "--showclocks", "--showfan",
+		"--json",
+	)
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute rocm-smi: %w", err)
 	}
 
+	return parseAMDOutput(out)
+}
+
+// parseAMDOutput parses the rocm-smi JSON, which maps cards to their fields. The field names
+// changed between ROCm releases, so the known alternatives are tried in order. rocm-smi does not
+// report the encoder and decoder utilization.
+func parseAMDOutput(out []byte) ([]GPUUsage, error) {
 	var data map[string]map[string]string
 	if err := json.Unmarshal(out, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	bracketRe := regexp.MustCompile(`\[(.*?)\]`)
 	var gpus []GPUUsage
 
 	for card, vals := range data {
-		var gpu GPUUsage
+		if !strings.HasPrefix(card, "card") {
+			// e.g. the system section
+			continue
+		}
+
+		gpu := newGPUUsage()
 		fmt.Sscanf(card, "card%d", &gpu.Index)
 
-		rawName := vals["Card Series"]
+		rawName := amdString(vals, "Card Series", "Card series")
 		if match := bracketRe.FindStringSubmatch(rawName); len(match) == 2 {
 			gpu.Name = match[1]
 		} else if rawName != "" {
@@ -42,25 +66,67 @@ func (p *AMDProvider) Read() ([]GPUUsage, error) {
 			gpu.Name = fmt.Sprintf("AMD GPU %d", gpu.Index)
 		}
 
-		gpu.Vendor = vals["Card Vendor"]
+		gpu.Vendor = amdString(vals, "Card Vendor", "Card vendor")
 		if gpu.Vendor == "" {
 			gpu.Vendor = "AMD"
 		}
 
-		fmt.Sscanf(vals["GPU use (%)"], "%d", &gpu.Utilization)
+		if utilization, ok := amdNumber(vals, "GPU use (%)"); ok {
+			gpu.Utilization = int(utilization)
+		}
 
-		var usedB, totalB float64
-		fmt.Sscanf(vals["VRAM Total Used Memory (B)"], "%f", &usedB)
-		fmt.Sscanf(vals["VRAM Total Memory (B)"], "%f", &totalB)
+		usedB, _ := amdNumber(vals, "VRAM Total Used Memory (B)")
+		totalB, _ := amdNumber(vals, "VRAM Total Memory (B)")
 		gpu.MemoryUsedMB = int(usedB / 1024 / 1024)
 		gpu.MemoryTotalMB = int(totalB / 1024 / 1024)
 
+		gpu.TemperatureC, _ = amdNumber(vals, "Temperature (Sensor edge) (C)", "Temperature (Sensor junction) (C)")
+		gpu.PowerW, _ = amdNumber(vals, "Average Graphics Package Power (W)", "Current Socket Graphics Package Power (W)")
+		gpu.PowerLimitW, _ = amdNumber(vals, "Max Graphics Package Power (W)")
+
+		coreClock, _ := amdNumber(vals, "sclk clock speed:")
+		memoryClock, _ := amdNumber(vals, "mclk clock speed:")
+		gpu.CoreClockMHz = int(coreClock)
+		gpu.MemoryClockMHz = int(memoryClock)
+
+		if fan, ok := amdNumber(vals, "Fan speed (%)"); ok {
+			gpu.FanPercent = int(fan)
+		}
+
 		gpus = append(gpus, gpu)
 	}
 
+	sort.Slice(gpus, func(i, j int) bool {
+		return gpus[i].Index < gpus[j].Index
+	})
 	return gpus, nil
 }
 
+// amdString returns the first of keys that is set, ROCm 6 capitalized the product fields.
+func amdString(vals map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := vals[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// amdNumber returns the number in the first of keys that has one.
+func amdNumber(vals map[string]string, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		match := numberRe.FindString(vals[key])
+		if match == "" {
+			continue
+		}
+		number, err := strconv.ParseFloat(match, 64)
+		if err == nil {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
 func CanUseAMDProvider() bool {
 	if _, err := exec.LookPath("rocm-smi"); err == nil {
 		return true
@@ -0,0 +1,116 @@
+package gpuinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// The captures in testdata/rocm-smi are the output of the rocm-smi command in AMDProvider.Read.
+// ROCm 5 names the product fields "Card series" and reports the average power, ROCm 6 uses
+// "Card Series" and the current socket power.
+func TestParseAMDOutput(t *testing.T) {
+	tests := []struct {
+		capture string
+		want    []GPUUsage
+	}{
+		{
+			capture: "rocm5.json",
+			want: []GPUUsage{
+				{
+					Index:              0,
+					Name:               "Radeon RX 6800/6800 XT / 6900 XT",
+					Vendor:             "Advanced Micro Devices, Inc. [AMD/ATI]",
+					Utilization:        3,
+					MemoryUsedMB:       1177,
+					MemoryTotalMB:      16368,
+					TemperatureC:       45,
+					PowerW:             31,
+					PowerLimitW:        255,
+					CoreClockMHz:       500,
+					MemoryClockMHz:     96,
+					FanPercent:         0,
+					EncoderUtilization: -1,
+					DecoderUtilization: -1,
+				},
+				{
+					// no edge sensor, the junction temperature is used instead
+					Index:              1,
+					Name:               "Instinct MI100",
+					Vendor:             "Advanced Micro Devices, Inc. [AMD/ATI]",
+					Utilization:        0,
+					MemoryUsedMB:       7,
+					MemoryTotalMB:      32752,
+					TemperatureC:       62,
+					PowerW:             38,
+					PowerLimitW:        290,
+					CoreClockMHz:       300,
+					MemoryClockMHz:     1200,
+					FanPercent:         -1,
+					EncoderUtilization: -1,
+					DecoderUtilization: -1,
+				},
+			},
+		},
+		{
+			capture: "rocm6.json",
+			want: []GPUUsage{
+				{
+					Index:              0,
+					Name:               "Radeon RX 7900 XT/7900 XTX/7900 GRE/7900M",
+					Vendor:             "Advanced Micro Devices, Inc. [AMD/ATI]",
+					Utilization:        37,
+					MemoryUsedMB:       3480,
+					MemoryTotalMB:      24560,
+					TemperatureC:       54,
+					PowerW:             187,
+					PowerLimitW:        303,
+					CoreClockMHz:       2482,
+					MemoryClockMHz:     1249,
+					FanPercent:         32,
+					EncoderUtilization: -1,
+					DecoderUtilization: -1,
+				},
+				{
+					// integrated GPU without power readings or a fan
+					Index:              1,
+					Name:               "Raphael",
+					Vendor:             "Advanced Micro Devices, Inc. [AMD/ATI]",
+					Utilization:        0,
+					MemoryUsedMB:       42,
+					MemoryTotalMB:      512,
+					TemperatureC:       41,
+					CoreClockMHz:       600,
+					MemoryClockMHz:     2400,
+					FanPercent:         -1,
+					EncoderUtilization: -1,
+					DecoderUtilization: -1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.capture, func(t *testing.T) {
+			out, err := os.ReadFile(filepath.Join("testdata", "rocm-smi", test.capture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gpus, err := parseAMDOutput(out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gpus, test.want) {
+				t.Errorf("got %+v, want %+v", gpus, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAMDOutputInvalidJSON(t *testing.T) {
+	if _, err := parseAMDOutput([]byte("WARNING: No AMD GPUs specified")); err == nil {
+		t.Error("expected an error")
+	}
+}
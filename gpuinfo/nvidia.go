@@ -8,6 +8,22 @@ import (
 	"strings"
 )
 
+// nvidiaQuery are the fields read from nvidia-smi, in the order of the csv columns.
+var nvidiaQuery = []string{
+	"name",
+	"utilization.gpu",
+	"memory.used",
+	"memory.total",
+	"temperature.gpu",
+	"power.draw",
+	"power.limit",
+	"clocks.gr",
+	"clocks.mem",
+	"fan.speed",
+	"utilization.encoder",
+	"utilization.decoder",
+}
+
 type NvidiaProvider struct {
 }
 
@@ -17,7 +33,7 @@ func NewNvidiaProvider() *NvidiaProvider {
 
 func (p *NvidiaProvider) Read() ([]GPUUsage, error) {
 	cmd := exec.Command("nvidia-smi",
-		"--query-gpu=name,utilization.gpu,memory.used,memory.total",
+		"--query-gpu="+strings.Join(nvidiaQuery, ","),
 		"--format=csv,noheader,nounits",
 	)
 	out, err := cmd.Output()
@@ -25,6 +41,12 @@ func (p *NvidiaProvider) Read() ([]GPUUsage, error) {
 		return nil, fmt.Errorf("failed to execute nvidia-smi: %w", err)
 	}
 
+	return parseNvidiaOutput(out)
+}
+
+// parseNvidiaOutput parses the csv output of the nvidia-smi query. Fields a GPU does not support
+// are reported as [N/A] or [Not Supported] and stay unknown.
+func parseNvidiaOutput(out []byte) ([]GPUUsage, error) {
 	scanner := bufio.NewScanner(strings.NewReader(string(out)))
 	var gpus []GPUUsage
 	index := 0
@@ -36,23 +58,30 @@ func (p *NvidiaProvider) Read() ([]GPUUsage, error) {
 		}
 
 		parts := strings.Split(line, ",")
-		if len(parts) < 4 {
+		if len(parts) < len(nvidiaQuery) {
 			continue
 		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+
+		gpu := newGPUUsage()
+		gpu.Index = index
+		gpu.Name = parts[0]
+		gpu.Vendor = "NVIDIA"
+		gpu.Utilization = parseNvidiaInt(parts[1], -1)
+		gpu.MemoryUsedMB = parseNvidiaInt(parts[2], 0)
+		gpu.MemoryTotalMB = parseNvidiaInt(parts[3], 0)
+		gpu.TemperatureC = parseNvidiaFloat(parts[4])
+		gpu.PowerW = parseNvidiaFloat(parts[5])
+		gpu.PowerLimitW = parseNvidiaFloat(parts[6])
+		gpu.CoreClockMHz = parseNvidiaInt(parts[7], 0)
+		gpu.MemoryClockMHz = parseNvidiaInt(parts[8], 0)
+		gpu.FanPercent = parseNvidiaInt(parts[9], -1)
+		gpu.EncoderUtilization = parseNvidiaInt(parts[10], -1)
+		gpu.DecoderUtilization = parseNvidiaInt(parts[11], -1)
 
-		name := strings.TrimSpace(parts[0])
-		util, _ := strconv.Atoi(strings.TrimSpace(parts[1]))
-		memUsed, _ := strconv.Atoi(strings.TrimSpace(parts[2]))
-		memTotal, _ := strconv.Atoi(strings.TrimSpace(parts[3]))
-
-		gpus = append(gpus, GPUUsage{
-			Index:         index,
-			Name:          name,
-			Vendor:        "NVIDIA",
-			Utilization:   util,
-			MemoryUsedMB:  memUsed,
-			MemoryTotalMB: memTotal,
-		})
+		gpus = append(gpus, gpu)
 		index++
 	}
 
@@ -63,6 +92,22 @@ func (p *NvidiaProvider) Read() ([]GPUUsage, error) {
 	return gpus, nil
 }
 
+func parseNvidiaInt(value string, unknown int) int {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return unknown
+	}
+	return int(number)
+}
+
+func parseNvidiaFloat(value string) float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
 func CanUseNvidiaProvider() bool {
 	if _, err := exec.LookPath("nvidia-smi"); err == nil {
 		return true
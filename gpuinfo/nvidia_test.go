@@ -0,0 +1,119 @@
+package gpuinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// The captures in testdata/nvidia-smi are the output of nvidia-smi with nvidiaQuery and
+// --format=csv,noheader,nounits.
+func TestParseNvidiaOutput(t *testing.T) {
+	tests := []struct {
+		capture string
+		want    []GPUUsage
+	}{
+		{
+			capture: "rtx4080.csv",
+			want: []GPUUsage{
+				{
+					Index:              0,
+					Name:               "NVIDIA GeForce RTX 4080",
+					Vendor:             "NVIDIA",
+					Utilization:        34,
+					MemoryUsedMB:       3480,
+					MemoryTotalMB:      16376,
+					TemperatureC:       61,
+					PowerW:             187.45,
+					PowerLimitW:        320,
+					CoreClockMHz:       2505,
+					MemoryClockMHz:     11201,
+					FanPercent:         32,
+					EncoderUtilization: 12,
+					DecoderUtilization: 0,
+				},
+			},
+		},
+		{
+			capture: "multi-gpu.csv",
+			want: []GPUUsage{
+				{
+					Index:              0,
+					Name:               "NVIDIA GeForce RTX 3090",
+					Vendor:             "NVIDIA",
+					Utilization:        97,
+					MemoryUsedMB:       20114,
+					MemoryTotalMB:      24576,
+					TemperatureC:       74,
+					PowerW:             341.87,
+					PowerLimitW:        350,
+					CoreClockMHz:       1845,
+					MemoryClockMHz:     9751,
+					FanPercent:         68,
+					EncoderUtilization: 0,
+					DecoderUtilization: 0,
+				},
+				{
+					// passively cooled, the fan is [N/A]
+					Index:              1,
+					Name:               "Tesla T4",
+					Vendor:             "NVIDIA",
+					Utilization:        0,
+					MemoryUsedMB:       0,
+					MemoryTotalMB:      15360,
+					TemperatureC:       38,
+					PowerW:             27.12,
+					PowerLimitW:        70,
+					CoreClockMHz:       300,
+					MemoryClockMHz:     405,
+					FanPercent:         -1,
+					EncoderUtilization: 0,
+					DecoderUtilization: 0,
+				},
+				{
+					// old card without utilization, power or encoder readings
+					Index:              2,
+					Name:               "NVIDIA GeForce GT 710",
+					Vendor:             "NVIDIA",
+					Utilization:        -1,
+					MemoryUsedMB:       230,
+					MemoryTotalMB:      2048,
+					TemperatureC:       40,
+					CoreClockMHz:       405,
+					MemoryClockMHz:     800,
+					FanPercent:         40,
+					EncoderUtilization: -1,
+					DecoderUtilization: -1,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.capture, func(t *testing.T) {
+			out, err := os.ReadFile(filepath.Join("testdata", "nvidia-smi", test.capture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gpus, err := parseNvidiaOutput(out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gpus, test.want) {
+				t.Errorf("got %+v, want %+v", gpus, test.want)
+			}
+		})
+	}
+}
+
+func TestParseNvidiaOutputSkipsShortLines(t *testing.T) {
+	gpus, err := parseNvidiaOutput([]byte("\nNVIDIA GeForce RTX 4080, 34\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gpus) != 0 {
+		t.Errorf("got %+v, want no GPUs", gpus)
+	}
+}
@@ -0,0 +1,295 @@
+package gpuinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PCIIDPaths are searched for the pci.ids database to name GPUs that do not report a name.
+var PCIIDPaths = []string{"/usr/share/hwdata/pci.ids", "/usr/share/misc/pci.ids", "/usr/share/pci.ids"}
+
+// PCI vendor IDs as found in the vendor file of a device.
+const (
+	VendorIDAMD    = "0x1002"
+	VendorIDNVIDIA = "0x10de"
+	VendorIDIntel  = "0x8086"
+)
+
+var pciVendors = map[string]string{
+	VendorIDAMD:    "AMD",
+	VendorIDNVIDIA: "NVIDIA",
+	VendorIDIntel:  "Intel",
+}
+
+var cardRe = regexp.MustCompile(`^card(\d+)$`)
+
+// SysfsProvider reads the GPUs from the DRM and hwmon interfaces of the kernel, it works without
+// vendor tools. Which values are available depends on the driver: amdgpu exposes everything, i915
+// and xe mostly only hwmon data and nouveau very little.
+type SysfsProvider struct {
+	root        string
+	skipVendors []string
+
+	// names caches the name of each card, the pci.ids database is too large to scan on every read
+	names      map[string]string
+	namesMutex sync.Mutex
+}
+
+// NewSysfsProvider reads from the sysfs mounted at root, usually /sys. Cards of skipVendors are
+// left out, which is used for vendors already covered by their own tools.
+func NewSysfsProvider(root string, skipVendors ...string) *SysfsProvider {
+	return &SysfsProvider{root: root, skipVendors: skipVendors, names: map[string]string{}}
+}
+
+func (p *SysfsProvider) Read() ([]GPUUsage, error) {
+	cards, err := p.cards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drm cards: %w", err)
+	}
+
+	gpus := []GPUUsage{}
+	for _, card := range cards {
+		device := filepath.Join(card, "device")
+
+		vendorID, err := readSysfsString(filepath.Join(device, "vendor"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gpu vendor: %w", err)
+		}
+
+		gpu := newGPUUsage()
+		gpu.Name = p.name(card, vendorID)
+		gpu.Vendor = pciVendors[vendorID]
+		fmt.Sscanf(filepath.Base(card), "card%d", &gpu.Index)
+
+		if busy, err := readSysfsInt(filepath.Join(device, "gpu_busy_percent")); err == nil {
+			gpu.Utilization = int(busy)
+		}
+
+		used, errUsed := readSysfsInt(filepath.Join(device, "mem_info_vram_used"))
+		total, errTotal := readSysfsInt(filepath.Join(device, "mem_info_vram_total"))
+		if errUsed == nil && errTotal == nil {
+			gpu.MemoryUsedMB = int(used / 1024 / 1024)
+			gpu.MemoryTotalMB = int(total / 1024 / 1024)
+		}
+
+		gpu.CoreClockMHz = readDPMClock(filepath.Join(device, "pp_dpm_sclk"))
+		gpu.MemoryClockMHz = readDPMClock(filepath.Join(device, "pp_dpm_mclk"))
+		if gpu.CoreClockMHz == 0 {
+			// i915 has no dpm tables but reports the current frequency of the card
+			if frequency, err := readSysfsInt(filepath.Join(card, "gt_cur_freq_mhz")); err == nil {
+				gpu.CoreClockMHz = int(frequency)
+			}
+		}
+
+		p.readHwmon(device, &gpu)
+		gpus = append(gpus, gpu)
+	}
+
+	return gpus, nil
+}
+
+// cards returns the card directories of known GPU vendors ordered by card number, connectors like
+// card0-DP-1 are skipped.
+func (p *SysfsProvider) cards() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(p.root, "class", "drm"))
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := map[string]int{}
+	cards := []string{}
+	for _, entry := range entries {
+		match := cardRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		card := filepath.Join(p.root, "class", "drm", entry.Name())
+		vendor, err := readSysfsString(filepath.Join(card, "device", "vendor"))
+		if err != nil {
+			continue
+		}
+		if _, ok := pciVendors[vendor]; !ok {
+			// e.g. simpledrm or virtual displays
+			continue
+		}
+		if slices.Contains(p.skipVendors, vendor) {
+			continue
+		}
+
+		numbers[card], _ = strconv.Atoi(match[1])
+		cards = append(cards, card)
+	}
+
+	sort.Slice(cards, func(i, j int) bool {
+		return numbers[cards[i]] < numbers[cards[j]]
+	})
+	return cards, nil
+}
+
+// name looks up the name of a card the first time it is seen and caches it.
+func (p *SysfsProvider) name(card string, vendorID string) string {
+	p.namesMutex.Lock()
+	defer p.namesMutex.Unlock()
+
+	if name, ok := p.names[card]; ok {
+		return name
+	}
+
+	device := filepath.Join(card, "device")
+	deviceID, _ := readSysfsString(filepath.Join(device, "device"))
+	name := lookupName(device, vendorID, deviceID)
+	p.names[card] = name
+	return name
+}
+
+func lookupName(device string, vendorID string, deviceID string) string {
+	// amdgpu knows the marketing name of some cards
+	if name, err := readSysfsString(filepath.Join(device, "product_name")); err == nil && name != "" {
+		return name
+	}
+
+	if name, ok := lookupPCIName(vendorID, deviceID); ok {
+		if match := bracketRe.FindStringSubmatch(name); len(match) == 2 {
+			return match[1]
+		}
+		return name
+	}
+
+	return fmt.Sprintf("%s GPU %s:%s", pciVendors[vendorID], strings.TrimPrefix(vendorID, "0x"), strings.TrimPrefix(deviceID, "0x"))
+}
+
+// readHwmon reads the temperature, power and fan of the first hwmon device of the GPU.
+func (p *SysfsProvider) readHwmon(device string, gpu *GPUUsage) {
+	hwmons, _ := filepath.Glob(filepath.Join(device, "hwmon", "hwmon*"))
+	if len(hwmons) == 0 {
+		return
+	}
+	sort.Strings(hwmons)
+	hwmon := hwmons[0]
+
+	temperature := "temp1"
+	// amdgpu has edge, junction and mem sensors, edge is what the vendor tools show by default
+	labels, _ := filepath.Glob(filepath.Join(hwmon, "temp*_label"))
+	for _, label := range labels {
+		if name, err := readSysfsString(label); err == nil && name == "edge" {
+			temperature = strings.TrimSuffix(filepath.Base(label), "_label")
+		}
+	}
+	if millidegrees, err := readSysfsInt(filepath.Join(hwmon, temperature+"_input")); err == nil {
+		gpu.TemperatureC = float64(millidegrees) / 1000
+	}
+
+	for _, name := range []string{"power1_average", "power1_input"} {
+		if microwatts, err := readSysfsInt(filepath.Join(hwmon, name)); err == nil {
+			gpu.PowerW = float64(microwatts) / 1000000
+			break
+		}
+	}
+	if microwatts, err := readSysfsInt(filepath.Join(hwmon, "power1_cap")); err == nil {
+		gpu.PowerLimitW = float64(microwatts) / 1000000
+	}
+
+	// pwm1 is only the duty cycle, which does not follow the fan in automatic mode on many boards
+	rpm, errRPM := readSysfsInt(filepath.Join(hwmon, "fan1_input"))
+	maxRPM, errMax := readSysfsInt(filepath.Join(hwmon, "fan1_max"))
+	if errRPM == nil && errMax == nil && maxRPM > 0 {
+		gpu.FanPercent = int(min(rpm*100/maxRPM, 100))
+	} else if pwm, err := readSysfsInt(filepath.Join(hwmon, "pwm1")); err == nil {
+		gpu.FanPercent = int(pwm * 100 / 255)
+	}
+}
+
+// readDPMClock returns the active level of an amdgpu dpm table like
+//
+//	0: 500Mhz
+//	1: 2482Mhz *
+func readDPMClock(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasSuffix(strings.TrimSpace(line), "*") {
+			continue
+		}
+		var level, clock int
+		if _, err := fmt.Sscanf(strings.ToLower(line), "%d: %dmhz", &level, &clock); err == nil {
+			return clock
+		}
+	}
+	return 0
+}
+
+// lookupPCIName finds the device name in the pci.ids database.
+func lookupPCIName(vendorID string, deviceID string) (string, bool) {
+	vendor := strings.TrimPrefix(vendorID, "0x")
+	device := strings.TrimPrefix(deviceID, "0x")
+
+	for _, path := range PCIIDPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		name, ok := scanPCIIDs(file, vendor, device)
+		file.Close()
+		return name, ok
+	}
+
+	return "", false
+}
+
+func scanPCIIDs(file *os.File, vendor string, device string) (string, bool) {
+	inVendor := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "\t") {
+			if inVendor {
+				break
+			}
+			inVendor = strings.HasPrefix(line, vendor+" ")
+			continue
+		}
+
+		// devices have one tab, subsystems two
+		if inVendor && !strings.HasPrefix(line, "\t\t") && strings.HasPrefix(line, "\t"+device+" ") {
+			return strings.TrimSpace(line[len(device)+1:]), true
+		}
+	}
+	return "", false
+}
+
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readSysfsInt(path string) (int64, error) {
+	text, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(text, 10, 64)
+}
+
+func CanUseSysfsProvider(root string, skipVendors ...string) bool {
+	cards, err := NewSysfsProvider(root, skipVendors...).cards()
+	return err == nil && len(cards) > 0
+}
@@ -0,0 +1,136 @@
+package gpuinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testdata/sysfs has an i915 laptop GPU as card0 and an amdgpu card as card1, next to their
+// connectors, a render node and a card of an unknown vendor.
+const sysfsTestRoot = "testdata/sysfs"
+
+var sysfsIntel = GPUUsage{
+	Index:              0,
+	Name:               "Iris Xe Graphics",
+	Vendor:             "Intel",
+	Utilization:        -1,
+	TemperatureC:       48,
+	CoreClockMHz:       1300,
+	FanPercent:         -1,
+	EncoderUtilization: -1,
+	DecoderUtilization: -1,
+}
+
+var sysfsAMD = GPUUsage{
+	Index:              1,
+	Name:               "Radeon RX 7900 XT/7900 XTX/7900 GRE/7900M",
+	Vendor:             "AMD",
+	Utilization:        37,
+	MemoryUsedMB:       3480,
+	MemoryTotalMB:      24560,
+	TemperatureC:       54,
+	PowerW:             187,
+	PowerLimitW:        303,
+	CoreClockMHz:       2482,
+	MemoryClockMHz:     1249,
+	FanPercent:         30,
+	EncoderUtilization: -1,
+	DecoderUtilization: -1,
+}
+
+func usePCIIDs(t *testing.T, paths ...string) {
+	previous := PCIIDPaths
+	PCIIDPaths = paths
+	t.Cleanup(func() { PCIIDPaths = previous })
+}
+
+func TestSysfsProviderRead(t *testing.T) {
+	usePCIIDs(t, "testdata/pci.ids")
+
+	tests := []struct {
+		name        string
+		skipVendors []string
+		want        []GPUUsage
+	}{
+		{"all", nil, []GPUUsage{sysfsIntel, sysfsAMD}},
+		{"amd covered by rocm-smi", []string{VendorIDAMD}, []GPUUsage{sysfsIntel}},
+		{"nvidia covered by nvidia-smi", []string{VendorIDNVIDIA}, []GPUUsage{sysfsIntel, sysfsAMD}},
+		{"everything covered", []string{VendorIDAMD, VendorIDIntel}, []GPUUsage{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gpus, err := NewSysfsProvider(sysfsTestRoot, test.skipVendors...).Read()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(gpus, test.want) {
+				t.Errorf("got %+v, want %+v", gpus, test.want)
+			}
+		})
+	}
+}
+
+func TestSysfsProviderCachesNames(t *testing.T) {
+	usePCIIDs(t, "testdata/pci.ids")
+	provider := NewSysfsProvider(sysfsTestRoot)
+	if _, err := provider.Read(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the database is not read again after the cards were discovered
+	PCIIDPaths = []string{"testdata/missing.ids"}
+	gpus, err := provider.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gpus[0].Name != sysfsIntel.Name || gpus[1].Name != sysfsAMD.Name {
+		t.Errorf("names changed: %q, %q", gpus[0].Name, gpus[1].Name)
+	}
+}
+
+func TestSysfsProviderNameWithoutPCIIDs(t *testing.T) {
+	usePCIIDs(t, "testdata/missing.ids")
+
+	gpus, err := NewSysfsProvider(sysfsTestRoot).Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gpus[0].Name != "Intel GPU 8086:46a6" || gpus[1].Name != "AMD GPU 1002:744c" {
+		t.Errorf("got %q, %q", gpus[0].Name, gpus[1].Name)
+	}
+}
+
+func TestCanUseSysfsProvider(t *testing.T) {
+	if !CanUseSysfsProvider(sysfsTestRoot) {
+		t.Error("expected the test tree to be usable")
+	}
+	if CanUseSysfsProvider(sysfsTestRoot, VendorIDAMD, VendorIDIntel) {
+		t.Error("expected no usable cards when all vendors are skipped")
+	}
+	if CanUseSysfsProvider("testdata/missing") {
+		t.Error("expected a missing root to be unusable")
+	}
+}
+
+func TestSysfsProviderFanFallsBackToPWM(t *testing.T) {
+	device := t.TempDir()
+	hwmon := filepath.Join(device, "hwmon", "hwmon0")
+	if err := os.MkdirAll(hwmon, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// without fan1_max the speed can not be put in relation, so the duty cycle is used
+	for name, value := range map[string]string{"fan1_input": "1012\n", "pwm1": "128\n"} {
+		if err := os.WriteFile(filepath.Join(hwmon, name), []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gpu := newGPUUsage()
+	NewSysfsProvider(sysfsTestRoot).readHwmon(device, &gpu)
+	if gpu.FanPercent != 50 {
+		t.Errorf("got %d, want 50", gpu.FanPercent)
+	}
+}
@@ -1,12 +1,35 @@
 package gpuinfo
 
 type GPUUsage struct {
-	Index         int
-	Name          string
+	Index int
+	Name  string
+	// Utilization is -1 if the driver does not report it.
 	Utilization   int
 	MemoryUsedMB  int
 	MemoryTotalMB int
 	Vendor        string
+	// TemperatureC, the power and the clocks are 0 if the provider can not read them.
+	TemperatureC   float64
+	PowerW         float64
+	PowerLimitW    float64
+	CoreClockMHz   int
+	MemoryClockMHz int
+	// FanPercent and the encoder and decoder utilization are -1 if unknown, 0 is a valid reading
+	// for them (e.g. zero RPM fans). The sysfs provider reports the fan speed relative to its
+	// maximum and falls back to the PWM duty cycle when the driver has no fan1_max.
+	FanPercent         int
+	EncoderUtilization int
+	DecoderUtilization int
+}
+
+// newGPUUsage returns a GPUUsage with all optional readings unknown.
+func newGPUUsage() GPUUsage {
+	return GPUUsage{
+		Utilization:        -1,
+		FanPercent:         -1,
+		EncoderUtilization: -1,
+		DecoderUtilization: -1,
+	}
 }
 
 type Provider interface {
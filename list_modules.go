@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func listModulesCommand(args []string) error {
+	flags, configPath := newFlagSet("list-modules")
+	flags.Parse(args)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tNAME\tCONFIG\tACTIVE")
+	for _, module := range createModules(config) {
+		section := module.ConfigSection()
+		if section == "" {
+			section = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%t\n", module.Id(), module.Name(), section, isModuleActive(config, module))
+	}
+	writer.Flush()
+
+	for _, module := range createModules(config) {
+		addresses := module.Addresses()
+		if len(addresses) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%s:\n", module.Id())
+		for _, address := range addresses {
+			fmt.Printf("  %s\n", address)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package locale
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Locale struct {
+	Language           string
+	Time12h            string
+	Time24h            string
+	Date               string
+	DecimalSeparator   string
+	ThousandsSeparator string
+	// PercentSpace puts a space between a number and the percent sign.
+	PercentSpace bool
+	Translations map[string]string
+}
+
+// New returns the built-in locale for language with the given translations added on top of it,
+// unknown languages fall back to English.
+func New(language string, translations map[string]string) *Locale {
+	base, ok := locales[language]
+	if !ok {
+		base = locales["en"]
+	}
+
+	locale := base
+	locale.Translations = map[string]string{}
+	for key, value := range base.Translations {
+		locale.Translations[key] = value
+	}
+	for key, value := range translations {
+		locale.Translations[key] = value
+	}
+
+	return &locale
+}
+
+func Exists(language string) bool {
+	_, ok := locales[language]
+	return ok
+}
+
+func Languages() []string {
+	languages := []string{}
+	for language := range locales {
+		languages = append(languages, language)
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// T translates a fixed string, untranslated strings are returned unchanged.
+func (l *Locale) T(text string) string {
+	if translated, ok := l.Translations[text]; ok {
+		return translated
+	}
+	return text
+}
+
+func (l *Locale) FormatTime12h(t time.Time) string {
+	formatted := t.Format(l.Time12h)
+	formatted = strings.Replace(formatted, "AM", l.T("AM"), 1)
+	formatted = strings.Replace(formatted, "PM", l.T("PM"), 1)
+	return formatted
+}
+
+func (l *Locale) FormatTime24h(t time.Time) string {
+	return t.Format(l.Time24h)
+}
+
+func (l *Locale) FormatDate(t time.Time) string {
+	return t.Format(l.Date)
+}
+
+// FormatNumber formats number with the given number of decimals and the locale's separators.
+func (l *Locale) FormatNumber(number float64, decimals int) string {
+	formatted := strconv.FormatFloat(math.Abs(number), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(formatted, ".")
+
+	grouped := &strings.Builder{}
+	if number < 0 && strings.Trim(formatted, "0.") != "" {
+		grouped.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(l.ThousandsSeparator)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if fraction != "" {
+		grouped.WriteString(l.DecimalSeparator + fraction)
+	}
+
+	return grouped.String()
+}
+
+func (l *Locale) Percent(percent int) string {
+	if l.PercentSpace {
+		return strconv.Itoa(percent) + " %"
+	}
+	return strconv.Itoa(percent) + "%"
+}
@@ -0,0 +1,91 @@
+package locale
+
+var locales = map[string]Locale{
+	"en": {
+		Language:           "en",
+		Time12h:            "03:04:05 PM",
+		Time24h:            "15:04:05",
+		Date:               "01/02/2006",
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		PercentSpace:       false,
+		Translations:       map[string]string{},
+	},
+	"de": {
+		Language:           "de",
+		Time12h:            "03:04:05 PM",
+		Time24h:            "15:04:05",
+		Date:               "02.01.2006",
+		DecimalSeparator:   ",",
+		ThousandsSeparator: ".",
+		PercentSpace:       true,
+		Translations: map[string]string{
+			"Playing":         "Spielt",
+			"Paused":          "Pausiert",
+			"Stopped":         "Gestoppt",
+			"nothing playing": "nichts läuft",
+		},
+	},
+	"fr": {
+		Language:           "fr",
+		Time12h:            "03:04:05 PM",
+		Time24h:            "15:04:05",
+		Date:               "02/01/2006",
+		DecimalSeparator:   ",",
+		ThousandsSeparator: " ",
+		PercentSpace:       true,
+		Translations: map[string]string{
+			"Playing":         "Lecture",
+			"Paused":          "En pause",
+			"Stopped":         "Arrêté",
+			"nothing playing": "rien en lecture",
+		},
+	},
+	"es": {
+		Language:           "es",
+		Time12h:            "03:04:05 PM",
+		Time24h:            "15:04:05",
+		Date:               "02/01/2006",
+		DecimalSeparator:   ",",
+		ThousandsSeparator: ".",
+		PercentSpace:       true,
+		Translations: map[string]string{
+			"Playing":         "Reproduciendo",
+			"Paused":          "En pausa",
+			"Stopped":         "Detenido",
+			"nothing playing": "nada en reproducción",
+		},
+	},
+	"nl": {
+		Language:           "nl",
+		Time12h:            "03:04:05 PM",
+		Time24h:            "15:04:05",
+		Date:               "02-01-2006",
+		DecimalSeparator:   ",",
+		ThousandsSeparator: ".",
+		PercentSpace:       false,
+		Translations: map[string]string{
+			"Playing":         "Speelt af",
+			"Paused":          "Gepauzeerd",
+			"Stopped":         "Gestopt",
+			"nothing playing": "niets aan het afspelen",
+		},
+	},
+	"ja": {
+		Language:           "ja",
+		Time12h:            "PM 3:04:05",
+		Time24h:            "15:04:05",
+		Date:               "2006/01/02",
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		PercentSpace:       false,
+		Translations: map[string]string{
+			"AM":              "午前",
+			"PM":              "午後",
+			"Playing":         "再生中",
+			"Paused":          "一時停止",
+			"Stopped":         "停止",
+			"nothing playing": "再生していません",
+		},
+	},
+}
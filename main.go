@@ -1,117 +1,216 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/control"
+	"github.com/Glowman554/OpenOSC/locale"
+	"github.com/Glowman554/OpenOSC/mpris"
 	"github.com/Glowman554/OpenOSC/oscmod"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/Glowman554/OpenOSC/oscmod/modules"
-	"github.com/hypebeast/go-osc/osc"
-	"github.com/mitchellh/go-ps"
+	"github.com/Glowman554/OpenOSC/output"
 )
 
-func isVRChatRunning() bool {
-	processes, err := ps.Processes()
-	if err != nil {
-		return false
-	}
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
 
-	for _, p := range processes {
-		if strings.Contains(strings.ToLower(p.Executable()), "vrchat") {
-			return true
-		}
-	}
-	return false
+var commands = []command{
+	{"run", "run [-config file]", "Run OpenOSC (default)", runCommand},
+	{"check-config", "check-config [-config file]", "Validate the config file", checkConfigCommand},
+	{"list-modules", "list-modules [-config file]", "List all available modules", listModulesCommand},
+	{"send", "send [-config file] <address> [args...]", "Send a raw OSC message to VRChat", sendCommand},
+	{"monitor", "monitor [-config file] [-filter pattern] [-changes]", "Print incoming OSC traffic", monitorCommand},
+	{"chatbox", "chatbox preview [-config file]", "Render the configured chatbox with sample values", chatboxCommand},
+	{"placeholders", "placeholders [-config file]", "List all placeholders provided by modules", placeholdersCommand},
 }
 
 func main() {
-	configPath := flag.String("config", "config.json", "Path to the config file")
-	flag.Parse()
+	name := "run"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name = args[0]
+		args = args[1:]
+	}
 
-	config, err := config.LoadConfig(*configPath)
+	if name == "help" {
+		usage()
+		return
+	}
 
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-		panic(err)
+	for _, command := range commands {
+		if command.name == name {
+			err := command.run(args)
+			if err != nil {
+				log.Fatalf("%s: %v", name, err)
+			}
+			return
+		}
 	}
 
-	// for true {
-	// 	log.Println("Waiting for VRChat")
+	fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [arguments]\n\nCommands:\n", os.Args[0])
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-52s %s\n", command.usage, command.description)
+	}
+}
 
-	// 	if isVRChatRunning() {
-	// 		break
-	// 	}
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := flags.String("config", "config.json", "Path to the config file")
+	return flags, configPath
+}
 
-	// 	time.Sleep(2 * time.Second)
-	// }
+func loadConfig(configPath string) (*config.Config, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
 
-	modules := []oscmod.OSCModule{
-		modules.NewMediaChatBoxModule(),
-		modules.NewMediaControlModule(),
-		modules.NewSysInfoModule(),
-		modules.NewGpuInfoModule(config.GpuInfo),
-		modules.NewOpenShockModule(config.OpenShockConfig),
-		modules.NewOpenShockControlModule(config.OpenShockConfig, config.OpenShockControlConfig),
-		modules.NewLeashModule(config.LeashConfig),
+	err = checkConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
 	}
-	activeModules := []oscmod.OSCModule{}
 
-	log.Println("Starting...")
+	return cfg, nil
+}
 
-	client := osc.NewClient(config.SendIP, config.SendPort)
+func newChatBox(cfg *config.Config) (*chatbox.ChatBoxBuilder, error) {
+	builder := chatbox.NewChatBoxBuilder()
+	builder.SetLocale(locale.New(cfg.Locale.Language, cfg.Locale.Translations))
+	builder.SetLimits(cfg.ChatboxConfig.MaxCharacters, cfg.ChatboxConfig.MaxLines, cfg.ChatboxConfig.Paginate)
+
+	defaultFlags := chatbox.SendFlags{Immediate: cfg.ChatboxConfig.Immediate, Notify: cfg.ChatboxConfig.Notify}
+	builder.SetSendOptions(
+		defaultFlags,
+		time.Duration(cfg.ChatboxConfig.KeepAliveSeconds)*time.Second,
+		cfg.ChatboxConfig.RateLimitBurst,
+		time.Duration(cfg.ChatboxConfig.RateLimitIntervalMS)*time.Millisecond,
+	)
+	builder.SetTransitionTyping(time.Duration(cfg.ChatboxConfig.TypingTransitionMS) * time.Millisecond)
+
+	pages := cfg.ChatboxConfig.Pages
+	if len(pages) == 0 {
+		pages = []config.ChatboxPage{{Name: "default", Lines: cfg.Chatbox}}
+	}
 
-	dispatcher := osc.NewStandardDispatcher()
-	server := &osc.Server{Addr: fmt.Sprintf("0.0.0.0:%d", config.ReceivePort), Dispatcher: dispatcher}
+	errs := []error{}
+	for i, page := range pages {
+		chatboxPage := builder.AddPage(page.Name, time.Duration(page.DurationSeconds)*time.Second)
+		if page.Immediate != nil || page.Notify != nil {
+			chatboxPage.SetFlags(*chatboxFlags(defaultFlags, page.ChatboxFlags))
+		}
+		for j, line := range page.Lines {
+			err := chatboxPage.AddLine(line.Text, line.Priority)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("page %d line %d: %w", i+1, j+1, err))
+			}
+		}
+	}
 
-	go func() {
-		err := server.ListenAndServe()
+	for i, message := range cfg.ChatboxConfig.ScheduledMessages {
+		err := builder.Schedule(
+			message.Text,
+			time.Duration(message.IntervalSeconds)*time.Second,
+			time.Duration(message.DurationSeconds)*time.Second,
+			chatboxFlags(defaultFlags, message.ChatboxFlags),
+		)
 		if err != nil {
-			log.Fatalf("Failed to listen: %v", err)
-			panic(err)
+			errs = append(errs, fmt.Errorf("scheduled message %d: %w", i+1, err))
 		}
-	}()
+	}
 
-	for _, module := range modules {
-		activate := false
-		for _, activeModuleId := range config.ActiveModules {
-			if module.Id() == activeModuleId {
-				activate = true
-			}
+	for i, outputConfig := range cfg.Outputs {
+		lines := []string{}
+		for _, line := range outputConfig.Lines {
+			lines = append(lines, line.Text)
 		}
 
-		if activate {
-			err := module.Init(client, dispatcher)
-			if err != nil {
-				log.Fatalf("Failed to initialize module: %s (%v)", module.Name(), err)
-			} else {
-				log.Printf("Initialized %s", module.Name())
-				activeModules = append(activeModules, module)
-			}
+		err := builder.AddOutput(newOutput(outputConfig), lines)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("output %d: %w", i+1, err))
 		}
 	}
 
-	chatbox := chatbox.NewChatBoxBuilder()
-	for _, i := range config.Chatbox {
-		chatbox.AddLine(i)
+	err := errors.Join(errs...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chatbox: %w", err)
 	}
 
-	for true {
-		chatbox.BeginTick()
+	return builder, nil
+}
 
-		for _, module := range activeModules {
-			err := module.Tick(client, chatbox)
-			if err != nil {
-				log.Printf("Failed to tick module: %s (%v)", module.Name(), err)
-			}
-		}
+// newOutput creates the output for a validated config, outputs only connect on their first write.
+func newOutput(cfg config.OutputConfig) chatbox.Output {
+	if cfg.Type == "obs" {
+		return output.NewOBSOutput(cfg.Address, cfg.Password, cfg.Source)
+	}
+	return output.NewFileOutput(cfg.Path)
+}
+
+// chatboxFlags applies the overrides of a page or message to the default flags.
+func chatboxFlags(defaults chatbox.SendFlags, overrides config.ChatboxFlags) *chatbox.SendFlags {
+	flags := defaults
+	if overrides.Immediate != nil {
+		flags.Immediate = *overrides.Immediate
+	}
+	if overrides.Notify != nil {
+		flags.Notify = *overrides.Notify
+	}
+	return &flags
+}
 
-		chatbox.EndTick(client, config.ChatboxDebug)
+func createModules(config *config.Config) []oscmod.OSCModule {
+	locale := locale.New(config.Locale.Language, config.Locale.Translations)
+	// the media modules share one connection and player cache and agree on the selected player
+	watcher := mpris.NewWatcher()
+	selector := mpris.NewSelector(watcher, config.Media.PreferredPlayers, config.Media.IgnoredPlayers, config.Media.PlayerPolicy)
+
+	return []oscmod.OSCModule{
+		modules.NewMediaChatBoxModule(selector, config.Media, locale),
+		modules.NewMediaControlModule(selector),
+		modules.NewMediaHistoryModule(selector, config.MediaHistory),
+		modules.NewMediaArtModule(selector, config.MediaArt),
+		modules.NewNotificationsModule(config.Notifications, locale),
+		modules.NewSysInfoModule(config.SysInfo, locale),
+		modules.NewProcessInfoModule(config.ProcessInfo, locale),
+		modules.NewGpuInfoModule(config.GpuInfo, locale),
+		modules.NewOpenShockModule(config.OpenShockConfig),
+		modules.NewOpenShockControlModule(config.OpenShockConfig, config.OpenShockControlConfig),
+		modules.NewLeashModule(config.LeashConfig),
+	}
+}
 
-		time.Sleep(2 * time.Second)
+func newPlaceholderRegistry(config *config.Config, modules []oscmod.OSCModule) *chatbox.PlaceholderRegistry {
+	registry := chatbox.NewPlaceholderRegistry()
+	for _, module := range modules {
+		registry.Register(module.Id(), isModuleActive(config, module), module.Placeholders())
 	}
+	registry.Register("dbus_control", config.DBusControl, control.Placeholders())
+	return registry
+}
+
+func isModuleActive(config *config.Config, module oscmod.OSCModule) bool {
+	for _, activeModuleId := range config.ActiveModules {
+		if module.Id() == activeModuleId {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hypebeast/go-osc/osc"
+)
+
+func monitorCommand(args []string) error {
+	flags, configPath := newFlagSet("monitor")
+	filter := flags.String("filter", "", "Only show addresses matching this pattern (* and ? are wildcards, otherwise substring match)")
+	exclude := flags.String("exclude", "", "Hide addresses matching this pattern")
+	changes := flags.Bool("changes", false, "Only show messages whose arguments changed")
+	port := flags.Int("port", 0, "Port to listen on (defaults to receivePort from the config)")
+	flags.Parse(args)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if *port == 0 {
+		*port = config.ReceivePort
+	}
+
+	include := compileAddressPattern(*filter)
+	excluded := compileAddressPattern(*exclude)
+
+	lastValues := map[string][]any{}
+	var mutex sync.Mutex
+
+	dispatcher := osc.NewStandardDispatcher()
+	dispatcher.AddMsgHandler("*", func(msg *osc.Message) {
+		if *filter != "" && !include.MatchString(msg.Address) {
+			return
+		}
+
+		if *exclude != "" && excluded.MatchString(msg.Address) {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if *changes {
+			if last, ok := lastValues[msg.Address]; ok && reflect.DeepEqual(last, msg.Arguments) {
+				return
+			}
+			lastValues[msg.Address] = msg.Arguments
+		}
+
+		fmt.Printf("%s  %-60s %s\n", time.Now().Format("15:04:05.000"), msg.Address, formatOSCArguments(msg.Arguments))
+	})
+
+	fmt.Printf("Listening on 0.0.0.0:%d\n", *port)
+
+	server := &osc.Server{Addr: fmt.Sprintf("0.0.0.0:%d", *port), Dispatcher: dispatcher}
+	return server.ListenAndServe()
+}
+
+func compileAddressPattern(pattern string) *regexp.Regexp {
+	if !strings.ContainsAny(pattern, "*?") {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+
+	expression := regexp.QuoteMeta(pattern)
+	expression = strings.ReplaceAll(expression, `\*`, ".*")
+	expression = strings.ReplaceAll(expression, `\?`, ".")
+	return regexp.MustCompile("^" + expression + "$")
+}
+
+func formatOSCArguments(arguments []any) string {
+	formatted := []string{}
+	for _, argument := range arguments {
+		switch value := argument.(type) {
+		case bool:
+			formatted = append(formatted, fmt.Sprintf("bool(%t)", value))
+		case int32:
+			formatted = append(formatted, fmt.Sprintf("int(%d)", value))
+		case float32:
+			formatted = append(formatted, fmt.Sprintf("float(%.4f)", value))
+		case string:
+			formatted = append(formatted, fmt.Sprintf("string(%q)", value))
+		default:
+			formatted = append(formatted, fmt.Sprintf("%T(%v)", value, value))
+		}
+	}
+	return strings.Join(formatted, " ")
+}
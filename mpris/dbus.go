@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/godbus/dbus/v5"
@@ -11,6 +12,10 @@ import (
 
 type DBUSInterface struct {
 	session *dbus.Conn
+
+	// mutedVolume is the volume of muted players to restore on unmute.
+	mutedVolume map[string]float64
+	mutex       sync.Mutex
 }
 
 func (d *DBUSInterface) Connect() error {
@@ -51,55 +56,113 @@ func (d *DBUSInterface) LoadCurrentlyPlaying(player string) (*CurrentlyPlaying,
 		log.Printf("Failed to get Metadata: %v", err)
 		return nil, err
 	}
-	metadata := metaVariant.Value().(map[string]dbus.Variant)
-
-	title := d.getString(metadata, "xesam:title")
-	artist := d.getStringList(metadata, "xesam:artist")
-	album := d.getString(metadata, "xesam:album")
-	duration := time.Duration(d.getInt64(metadata, "mpris:length")) * time.Microsecond
+	metadata, ok := metaVariant.Value().(map[string]dbus.Variant)
+	if !ok {
+		err = &MetadataError{Field: "Metadata", Value: metaVariant.Value()}
+		log.Printf("Failed to read Metadata of %s: %v", player, err)
+		return nil, err
+	}
 
-	posVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Position")
+	position, err := d.LoadPosition(player)
 	if err != nil {
-		log.Printf("Failed to get Position: %v", err)
 		return nil, err
 	}
 
-	position := time.Duration(posVariant.Value().(int64)) * time.Microsecond
-
 	statusVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.PlaybackStatus")
 	if err != nil {
 		log.Printf("Failed to get PlaybackStatus: %v", err)
 		return nil, err
 	}
 
-	status := d.stringToStatus(statusVariant.Value().(string))
+	statusString, ok := statusVariant.Value().(string)
+	if !ok {
+		err = fmt.Errorf("invalid type for PlaybackStatus: %T", statusVariant.Value())
+		log.Printf("Failed to read PlaybackStatus of %s: %v", player, err)
+		return nil, err
+	}
+	status := d.stringToStatus(statusString)
 
 	shuffleVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Shuffle")
 	shuffle := false
 	if err != nil {
 		// log.Printf("Failed to get Shuffle: %v", err)
 	} else {
-		shuffle = shuffleVariant.Value().(bool)
+		shuffle, _ = shuffleVariant.Value().(bool)
 	}
 
 	loopVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.LoopStatus")
 	loopStatus := None
 	if err != nil {
 		// log.Printf("Failed to get LoopStatus: %v", err)
-	} else {
-		loopStatus = d.stringToLoopType(loopVariant.Value().(string))
+	} else if loop, ok := loopVariant.Value().(string); ok {
+		loopStatus = d.stringToLoopType(loop)
+	}
+
+	volumeVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Volume")
+	volume := 1.0
+	if err == nil {
+		if value, ok := volumeVariant.Value().(float64); ok {
+			volume = value
+		}
+	}
+
+	rateVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Rate")
+	rate := 1.0
+	if err == nil {
+		if value, ok := rateVariant.Value().(float64); ok && value > 0 {
+			rate = value
+		}
 	}
 
-	return &CurrentlyPlaying{
-		Title:    title,
-		Artist:   artist,
-		Album:    album,
-		Duration: duration,
+	playing := &CurrentlyPlaying{
 		Position: position,
 		Status:   status,
 		Shuffle:  shuffle,
 		Loop:     loopStatus,
-	}, nil
+		Volume:   volume,
+		Rate:     rate,
+	}
+	d.readMetadata(player, metadata, playing)
+
+	return playing, nil
+}
+
+func (d *DBUSInterface) LoadPosition(player string) (time.Duration, error) {
+	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
+	posVariant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Position")
+	if err != nil {
+		log.Printf("Failed to get Position: %v", err)
+		return 0, err
+	}
+
+	position, ok := toInt64(posVariant.Value())
+	if !ok {
+		err = fmt.Errorf("invalid type for Position: %T", posVariant.Value())
+		log.Printf("Failed to read Position of %s: %v", player, err)
+		return 0, err
+	}
+
+	return time.Duration(position) * time.Microsecond, nil
+}
+
+// readMetadata copies the metadata into playing, fields with an unexpected type are logged and
+// left empty.
+func (d *DBUSInterface) readMetadata(player string, metadata map[string]dbus.Variant, playing *CurrentlyPlaying) {
+	parsed, err := ParseMetadata(metadata)
+	if err != nil {
+		log.Printf("Invalid metadata from %s: %v", player, err)
+	}
+
+	playing.Title = parsed.Title
+	playing.Artist = parsed.Artist
+	playing.AlbumArtist = parsed.AlbumArtist
+	playing.Album = parsed.Album
+	playing.TrackNumber = parsed.TrackNumber
+	playing.Genre = parsed.Genre
+	playing.URL = parsed.URL
+	playing.ArtURL = parsed.ArtURL
+	playing.UserRating = parsed.UserRating
+	playing.Duration = parsed.Length
 }
 
 func (d *DBUSInterface) commonCall(player string, command string) error {
@@ -150,84 +213,134 @@ func (d *DBUSInterface) Shuffle(player string, enabled bool) error {
 	return nil
 }
 
-func (d *DBUSInterface) Loop(player string, status LoopType) error {
+func (d *DBUSInterface) Volume(player string, volume float64) error {
 	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
 
-	call := obj.Call("org.freedesktop.DBus.Properties.Set", 0, "org.mpris.MediaPlayer2.Player", "LoopStatus", dbus.MakeVariant(status))
+	call := obj.Call("org.freedesktop.DBus.Properties.Set", 0, "org.mpris.MediaPlayer2.Player", "Volume", dbus.MakeVariant(max(volume, 0)))
 	if call.Err != nil {
-		// log.Printf("Failed to set loop: %v", call.Err)
+		log.Printf("Failed to set volume: %v", call.Err)
 		return call.Err
 	}
 
 	return nil
 }
 
-func (d *DBUSInterface) Seek(player string, position float32) error {
+// Mute sets the volume to 0 and restores the previous volume on unmute, players without a
+// remembered volume are unmuted to full volume.
+func (d *DBUSInterface) Mute(player string, muted bool) error {
 	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
 
-	variant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Metadata")
+	variant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Volume")
 	if err != nil {
-		log.Printf("Failed to get Metadata: %v", err)
+		log.Printf("Failed to get Volume: %v", err)
 		return err
 	}
+	volume, ok := variant.Value().(float64)
+	if !ok {
+		return fmt.Errorf("invalid type for Volume: %T", variant.Value())
+	}
 
-	metadata := variant.Value().(map[string]dbus.Variant)
-
-	durationMicros := d.getInt64(metadata, "mpris:length")
+	d.mutex.Lock()
+	if d.mutedVolume == nil {
+		d.mutedVolume = map[string]float64{}
+	}
+	restore, remembered := d.mutedVolume[player]
+	if muted && volume > 0 {
+		d.mutedVolume[player] = volume
+	}
+	if !muted {
+		delete(d.mutedVolume, player)
+	}
+	d.mutex.Unlock()
 
-	raw := metadata["mpris:trackid"].Value()
-	var trackId dbus.ObjectPath
+	if muted {
+		if volume == 0 {
+			return nil
+		}
+		return d.Volume(player, 0)
+	}
 
-	switch v := raw.(type) {
-	case dbus.ObjectPath:
-		trackId = v
-	case string:
-		trackId = dbus.ObjectPath(v)
-	default:
-		log.Printf("Unexpected type for mpris:trackid: %T", v)
-		return fmt.Errorf("invalid type for trackid: %T", v)
+	if volume > 0 {
+		return nil
+	}
+	if !remembered {
+		restore = 1
 	}
+	return d.Volume(player, restore)
+}
 
-	targetMicros := int64(position * float32(durationMicros))
+// Rate sets the playback rate, it is clamped to the range the player supports.
+func (d *DBUSInterface) Rate(player string, rate float64) error {
+	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
 
-	call := obj.Call("org.mpris.MediaPlayer2.Player.SetPosition", 0, trackId, targetMicros)
+	// a rate of 0 would pause the player
+	minimum, maximum := 0.1, rate
+	if variant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.MinimumRate"); err == nil {
+		if value, ok := variant.Value().(float64); ok && value > 0 {
+			minimum = value
+		}
+	}
+	if variant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.MaximumRate"); err == nil {
+		if value, ok := variant.Value().(float64); ok && value > 0 {
+			maximum = value
+		}
+	}
+	rate = max(min(rate, maximum), minimum)
+
+	call := obj.Call("org.freedesktop.DBus.Properties.Set", 0, "org.mpris.MediaPlayer2.Player", "Rate", dbus.MakeVariant(rate))
 	if call.Err != nil {
-		log.Printf("Failed to seek: %v", call.Err)
+		log.Printf("Failed to set rate: %v", call.Err)
 		return call.Err
 	}
 
 	return nil
 }
 
-func (d *DBUSInterface) getString(m map[string]dbus.Variant, key string) string {
-	if v, ok := m[key]; ok {
-		return v.Value().(string)
+func (d *DBUSInterface) Loop(player string, status LoopType) error {
+	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
+
+	call := obj.Call("org.freedesktop.DBus.Properties.Set", 0, "org.mpris.MediaPlayer2.Player", "LoopStatus", dbus.MakeVariant(status))
+	if call.Err != nil {
+		// log.Printf("Failed to set loop: %v", call.Err)
+		return call.Err
 	}
-	return ""
+
+	return nil
 }
 
-func (d *DBUSInterface) getStringList(m map[string]dbus.Variant, key string) []string {
-	if v, ok := m[key]; ok {
-		return v.Value().([]string)
+func (d *DBUSInterface) Seek(player string, position float32) error {
+	obj := d.session.Object(player, "/org/mpris/MediaPlayer2")
+
+	variant, err := obj.GetProperty("org.mpris.MediaPlayer2.Player.Metadata")
+	if err != nil {
+		log.Printf("Failed to get Metadata: %v", err)
+		return err
+	}
+
+	metadata, ok := variant.Value().(map[string]dbus.Variant)
+	if !ok {
+		return &MetadataError{Field: "Metadata", Value: variant.Value()}
 	}
-	return []string{}
-}
 
-func (d *DBUSInterface) getInt64(m map[string]dbus.Variant, key string) int64 {
-	if v, ok := m[key]; ok {
-		switch val := v.Value().(type) {
-		case int64:
-			return val
-		case uint64:
-			return int64(val)
-		case float64: // why haruna??
-			return int64(val)
-		default:
-			log.Printf("Unexpected type for key %s: %T", key, val)
-			return 0
+	parsed, err := ParseMetadata(metadata)
+	if parsed.TrackID == "" {
+		if err == nil {
+			err = fmt.Errorf("player did not send mpris:trackid")
 		}
+		log.Printf("Failed to seek: %v", err)
+		return err
+	}
+
+	targetMicros := int64(float64(position) * float64(parsed.Length.Microseconds()))
+	trackId := parsed.TrackID
+
+	call := obj.Call("org.mpris.MediaPlayer2.Player.SetPosition", 0, trackId, targetMicros)
+	if call.Err != nil {
+		log.Printf("Failed to seek: %v", call.Err)
+		return call.Err
 	}
-	return 0
+
+	return nil
 }
 
 func (d *DBUSInterface) stringToStatus(status string) PlayStatus {
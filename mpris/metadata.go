@@ -0,0 +1,208 @@
+package mpris
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// MetadataError is a metadata field a player sent with an unexpected type, the field is left
+// empty and the rest of the metadata is still used.
+type MetadataError struct {
+	Field string
+	Value any
+}
+
+func (e *MetadataError) Error() string {
+	return fmt.Sprintf("metadata field %s has unexpected type %T", e.Field, e.Value)
+}
+
+// Metadata is the decoded xesam and mpris metadata of a track.
+type Metadata struct {
+	TrackID     dbus.ObjectPath
+	Title       string
+	Artist      []string
+	AlbumArtist []string
+	Album       string
+	Length      time.Duration
+	TrackNumber int
+	URL         string
+	ArtURL      string
+	Genre       []string
+	// UserRating is between 0 and 1.
+	UserRating float64
+}
+
+// ParseMetadata decodes the Metadata property of a player. It never panics, fields with an
+// unexpected type are reported as *MetadataError joined into err and left empty.
+func ParseMetadata(metadata map[string]dbus.Variant) (Metadata, error) {
+	d := metadataDecoder{metadata: metadata}
+
+	parsed := Metadata{
+		TrackID:     d.objectPath("mpris:trackid"),
+		Title:       d.string("xesam:title"),
+		Artist:      d.stringList("xesam:artist"),
+		AlbumArtist: d.stringList("xesam:albumArtist"),
+		Album:       d.string("xesam:album"),
+		Length:      time.Duration(d.int64("mpris:length")) * time.Microsecond,
+		TrackNumber: int(d.int64("xesam:trackNumber")),
+		URL:         d.string("xesam:url"),
+		ArtURL:      d.string("mpris:artUrl"),
+		Genre:       d.stringList("xesam:genre"),
+		UserRating:  d.float64("xesam:userRating"),
+	}
+
+	return parsed, errors.Join(d.errs...)
+}
+
+type metadataDecoder struct {
+	metadata map[string]dbus.Variant
+	errs     []error
+}
+
+func (d *metadataDecoder) value(key string) (any, bool) {
+	variant, ok := d.metadata[key]
+	if !ok {
+		return nil, false
+	}
+	return variant.Value(), true
+}
+
+func (d *metadataDecoder) fail(key string, value any) {
+	d.errs = append(d.errs, &MetadataError{Field: key, Value: value})
+}
+
+func (d *metadataDecoder) string(key string) string {
+	value, ok := d.value(key)
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case dbus.ObjectPath:
+		return string(v)
+	case []string:
+		// some players send single values as lists
+		if len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	default:
+		d.fail(key, value)
+		return ""
+	}
+}
+
+func (d *metadataDecoder) stringList(key string) []string {
+	value, ok := d.value(key)
+	if !ok {
+		return []string{}
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v
+	case string:
+		// some players send single values as plain strings
+		return []string{v}
+	case []any:
+		list := []string{}
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				d.fail(key, value)
+				return []string{}
+			}
+			list = append(list, s)
+		}
+		return list
+	case []dbus.Variant:
+		list := []string{}
+		for _, item := range v {
+			s, ok := item.Value().(string)
+			if !ok {
+				d.fail(key, value)
+				return []string{}
+			}
+			list = append(list, s)
+		}
+		return list
+	default:
+		d.fail(key, value)
+		return []string{}
+	}
+}
+
+func (d *metadataDecoder) int64(key string) int64 {
+	value, ok := d.value(key)
+	if !ok {
+		return 0
+	}
+
+	number, ok := toInt64(value)
+	if !ok {
+		d.fail(key, value)
+	}
+	return number
+}
+
+func (d *metadataDecoder) float64(key string) float64 {
+	value, ok := d.value(key)
+	if !ok {
+		return 0
+	}
+
+	if v, ok := value.(float64); ok {
+		return v
+	}
+	number, ok := toInt64(value)
+	if !ok {
+		d.fail(key, value)
+	}
+	return float64(number)
+}
+
+func (d *metadataDecoder) objectPath(key string) dbus.ObjectPath {
+	value, ok := d.value(key)
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case dbus.ObjectPath:
+		return v
+	case string:
+		return dbus.ObjectPath(v)
+	default:
+		d.fail(key, value)
+		return ""
+	}
+}
+
+// toInt64 accepts every integer type players are known to send, and floats (why haruna??).
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case byte:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,291 @@
+package mpris
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func variants(values map[string]any) map[string]dbus.Variant {
+	metadata := map[string]dbus.Variant{}
+	for key, value := range values {
+		metadata[key] = dbus.MakeVariant(value)
+	}
+	return metadata
+}
+
+// The fixtures are hand-written to match the metadata these players send, including the D-Bus type
+// each of them uses for the keys.
+var metadataFixtures = []struct {
+	player   string
+	metadata map[string]dbus.Variant
+	expected Metadata
+}{
+	{
+		player: "spotify",
+		metadata: variants(map[string]any{
+			"mpris:trackid":     dbus.ObjectPath("/com/spotify/track/4uLU6hMCjMI75M1A2tKUQC"),
+			"mpris:length":      uint64(213573000),
+			"mpris:artUrl":      "https://i.scdn.co/image/ab67616d0000b273ba5db46f4b838ef6027e6f96",
+			"xesam:album":       "Whenever You Need Somebody",
+			"xesam:albumArtist": []string{"Rick Astley"},
+			"xesam:artist":      []string{"Rick Astley"},
+			"xesam:autoRating":  0.79,
+			"xesam:discNumber":  int32(1),
+			"xesam:title":       "Never Gonna Give You Up",
+			"xesam:trackNumber": int32(1),
+			"xesam:url":         "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+		}),
+		expected: Metadata{
+			TrackID:     "/com/spotify/track/4uLU6hMCjMI75M1A2tKUQC",
+			Title:       "Never Gonna Give You Up",
+			Artist:      []string{"Rick Astley"},
+			AlbumArtist: []string{"Rick Astley"},
+			Album:       "Whenever You Need Somebody",
+			Length:      213573 * time.Millisecond,
+			TrackNumber: 1,
+			URL:         "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC",
+			ArtURL:      "https://i.scdn.co/image/ab67616d0000b273ba5db46f4b838ef6027e6f96",
+			Genre:       []string{},
+		},
+	},
+	{
+		player: "firefox",
+		metadata: variants(map[string]any{
+			"mpris:trackid": dbus.ObjectPath("/org/mpris/MediaPlayer2/firefox"),
+			"mpris:length":  int64(212000000),
+			"mpris:artUrl":  "file:///home/user/.mozilla/firefox/firefox-mpris/12345_0.png",
+			"xesam:album":   "",
+			"xesam:artist":  []string{"Rick Astley"},
+			"xesam:title":   "Rick Astley - Never Gonna Give You Up (Official Music Video)",
+		}),
+		expected: Metadata{
+			TrackID:     "/org/mpris/MediaPlayer2/firefox",
+			Title:       "Rick Astley - Never Gonna Give You Up (Official Music Video)",
+			Artist:      []string{"Rick Astley"},
+			AlbumArtist: []string{},
+			Length:      212 * time.Second,
+			ArtURL:      "file:///home/user/.mozilla/firefox/firefox-mpris/12345_0.png",
+			Genre:       []string{},
+		},
+	},
+	{
+		player: "mpv",
+		metadata: variants(map[string]any{
+			"mpris:trackid":     dbus.ObjectPath("/io/mpv/playlist/0"),
+			"mpris:length":      int64(182347000),
+			"xesam:album":       "Discovery",
+			"xesam:albumArtist": []string{"Daft Punk"},
+			"xesam:artist":      []string{"Daft Punk"},
+			"xesam:genre":       []string{"Electronic", "House"},
+			"xesam:title":       "One More Time",
+			"xesam:trackNumber": int32(1),
+			"xesam:url":         "file:///home/user/Music/Daft%20Punk/Discovery/01%20One%20More%20Time.flac",
+		}),
+		expected: Metadata{
+			TrackID:     "/io/mpv/playlist/0",
+			Title:       "One More Time",
+			Artist:      []string{"Daft Punk"},
+			AlbumArtist: []string{"Daft Punk"},
+			Album:       "Discovery",
+			Length:      182347 * time.Millisecond,
+			TrackNumber: 1,
+			URL:         "file:///home/user/Music/Daft%20Punk/Discovery/01%20One%20More%20Time.flac",
+			Genre:       []string{"Electronic", "House"},
+		},
+	},
+	{
+		player: "chromium",
+		metadata: variants(map[string]any{
+			"mpris:trackid": dbus.ObjectPath("/org/chromium/MediaPlayer2/TrackList/Track_7E2D3F4C"),
+			"mpris:length":  int64(0),
+			"mpris:artUrl":  "file:///tmp/.org.chromium.Chromium.aBcD12",
+			"xesam:album":   "",
+			"xesam:artist":  []string{"Lofi Girl"},
+			"xesam:title":   "lofi hip hop radio 📚 beats to relax/study to",
+		}),
+		expected: Metadata{
+			TrackID:     "/org/chromium/MediaPlayer2/TrackList/Track_7E2D3F4C",
+			Title:       "lofi hip hop radio 📚 beats to relax/study to",
+			Artist:      []string{"Lofi Girl"},
+			AlbumArtist: []string{},
+			ArtURL:      "file:///tmp/.org.chromium.Chromium.aBcD12",
+			Genre:       []string{},
+		},
+	},
+}
+
+func TestParseMetadataFixtures(t *testing.T) {
+	for _, fixture := range metadataFixtures {
+		t.Run(fixture.player, func(t *testing.T) {
+			parsed, err := ParseMetadata(fixture.metadata)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, fixture.expected) {
+				t.Errorf("got %+v, want %+v", parsed, fixture.expected)
+			}
+		})
+	}
+}
+
+func TestParseMetadataLength(t *testing.T) {
+	tests := []struct {
+		name   string
+		length any
+		want   time.Duration
+	}{
+		{"int64", int64(3000000), 3 * time.Second},
+		{"uint64", uint64(3000000), 3 * time.Second},
+		{"int32", int32(3000000), 3 * time.Second},
+		// haruna sends the length as a double
+		{"float64", float64(3000000), 3 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := ParseMetadata(variants(map[string]any{"mpris:length": test.length}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parsed.Length != test.want {
+				t.Errorf("got %v, want %v", parsed.Length, test.want)
+			}
+		})
+	}
+}
+
+func TestParseMetadataArtistForms(t *testing.T) {
+	tests := []struct {
+		name   string
+		artist any
+		want   []string
+	}{
+		{"list", []string{"A", "B"}, []string{"A", "B"}},
+		{"string", "A", []string{"A"}},
+		{"any list", []any{"A", "B"}, []string{"A", "B"}},
+		{"variant list", []dbus.Variant{dbus.MakeVariant("A"), dbus.MakeVariant("B")}, []string{"A", "B"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadata := map[string]dbus.Variant{"xesam:artist": dbus.MakeVariant(test.artist)}
+			parsed, err := ParseMetadata(metadata)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Artist, test.want) {
+				t.Errorf("got %v, want %v", parsed.Artist, test.want)
+			}
+		})
+	}
+}
+
+func TestParseMetadataWrongTypes(t *testing.T) {
+	metadata := variants(map[string]any{
+		"xesam:title":   int32(42),
+		"xesam:artist":  []any{"A", int32(1)},
+		"mpris:length":  "three minutes",
+		"mpris:trackid": int32(7),
+		"xesam:album":   "Album",
+	})
+
+	parsed, err := ParseMetadata(metadata)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	fields := map[string]bool{}
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var metadataErr *MetadataError
+		if !errors.As(err, &metadataErr) {
+			t.Fatalf("unexpected error type %T", err)
+		}
+		fields[metadataErr.Field] = true
+	}
+	for _, field := range []string{"xesam:title", "xesam:artist", "mpris:length", "mpris:trackid"} {
+		if !fields[field] {
+			t.Errorf("no error for %s", field)
+		}
+	}
+
+	// the valid fields are still used
+	if parsed.Album != "Album" {
+		t.Errorf("album: got %q", parsed.Album)
+	}
+	if parsed.Title != "" || len(parsed.Artist) != 0 || parsed.Length != 0 {
+		t.Errorf("invalid fields were not left empty: %+v", parsed)
+	}
+}
+
+func TestParseMetadataMissingKeys(t *testing.T) {
+	parsed, err := ParseMetadata(map[string]dbus.Variant{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Metadata{Artist: []string{}, AlbumArtist: []string{}, Genre: []string{}}
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("got %+v, want %+v", parsed, want)
+	}
+}
+
+// metadataFuzzValue turns the fuzzer input into the different types players were seen sending.
+func metadataFuzzValue(kind uint8, text string, number int64, float float64) any {
+	switch kind % 10 {
+	case 0:
+		return text
+	case 1:
+		return number
+	case 2:
+		return uint64(number)
+	case 3:
+		return int32(number)
+	case 4:
+		return float
+	case 5:
+		return []string{text, text}
+	case 6:
+		return []any{text, number}
+	case 7:
+		return []dbus.Variant{dbus.MakeVariant(text), dbus.MakeVariant(float)}
+	case 8:
+		return dbus.ObjectPath(text)
+	default:
+		return map[string]dbus.Variant{text: dbus.MakeVariant(number)}
+	}
+}
+
+func FuzzParseMetadata(f *testing.F) {
+	f.Add("xesam:title", uint8(0), "Title", int64(1), 1.5)
+	f.Add("xesam:artist", uint8(6), "Artist", int64(-1), 0.0)
+	f.Add("mpris:length", uint8(4), "", int64(0), 213573000.0)
+	f.Add("xesam:userRating", uint8(9), "x", int64(5), -1.0)
+
+	keys := []string{
+		"mpris:trackid", "mpris:length", "mpris:artUrl", "xesam:title", "xesam:artist",
+		"xesam:albumArtist", "xesam:album", "xesam:trackNumber", "xesam:url", "xesam:genre",
+		"xesam:userRating",
+	}
+
+	f.Fuzz(func(t *testing.T, key string, kind uint8, text string, number int64, float float64) {
+		metadata := map[string]dbus.Variant{key: dbus.MakeVariant(metadataFuzzValue(kind, text, number, float))}
+		for i, known := range keys {
+			metadata[known] = dbus.MakeVariant(metadataFuzzValue(kind+uint8(i), text, number, float))
+		}
+
+		_, err := ParseMetadata(metadata)
+		if err == nil {
+			return
+		}
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var metadataErr *MetadataError
+			if !errors.As(err, &metadataErr) {
+				t.Fatalf("unexpected error type %T", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package mpris
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PolicyRecent prefers the player that most recently started playing.
+	PolicyRecent = "recent"
+	// PolicyFirst prefers the player that appeared on the bus first.
+	PolicyFirst = "first"
+)
+
+var Policies = []string{PolicyRecent, PolicyFirst}
+
+func IsPolicy(policy string) bool {
+	return slices.Contains(Policies, policy)
+}
+
+// Selector picks the player the media modules show and control. Players are matched by their bus
+// name without the org.mpris.MediaPlayer2. prefix (e.g. "spotify" or "firefox") or their identity,
+// case insensitive.
+type Selector struct {
+	watcher   *Watcher
+	preferred []string
+	ignored   []string
+	policy    string
+
+	// selected is set by Cycle and overrides the policy while the player exists.
+	selected string
+	mutex    sync.Mutex
+}
+
+func NewSelector(watcher *Watcher, preferred []string, ignored []string, policy string) *Selector {
+	return &Selector{
+		watcher:   watcher,
+		preferred: preferred,
+		ignored:   ignored,
+		policy:    policy,
+	}
+}
+
+type candidate struct {
+	player  string
+	playing *CurrentlyPlaying
+	state   playerState
+}
+
+// Current returns the selected player. Playing players win over paused ones, the preferred
+// list decides between them before the policy. Stopped players are never selected unless cycled to.
+func (s *Selector) Current() (string, *CurrentlyPlaying, bool) {
+	candidates := s.candidates()
+
+	s.mutex.Lock()
+	selected := s.selected
+	s.mutex.Unlock()
+
+	if selected != "" {
+		for _, c := range candidates {
+			if c.player == selected {
+				return c.player, c.playing, true
+			}
+		}
+
+		s.mutex.Lock()
+		if s.selected == selected {
+			s.selected = ""
+		}
+		s.mutex.Unlock()
+	}
+
+	for _, status := range []PlayStatus{Playing, Paused} {
+		best := -1
+		for i, c := range candidates {
+			if c.playing.Status == status && (best < 0 || s.better(c, candidates[best])) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			return candidates[best].player, candidates[best].playing, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// Cycle selects the next player after the current one, the selection sticks until the player
+// disappears.
+func (s *Selector) Cycle() (string, bool) {
+	candidates := s.candidates()
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	current, _, _ := s.Current()
+	next := 0
+	for i, c := range candidates {
+		if c.player == current {
+			next = (i + 1) % len(candidates)
+			break
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.selected = candidates[next].player
+	return s.selected, true
+}
+
+// Watcher returns the watcher the selector picks players from.
+func (s *Selector) Watcher() *Watcher {
+	return s.watcher
+}
+
+// Identity returns the friendly name of player, see Watcher.Identity.
+func (s *Selector) Identity(player string) string {
+	return s.watcher.Identity(player)
+}
+
+func (s *Selector) better(a candidate, b candidate) bool {
+	rankA, rankB := s.rank(a), s.rank(b)
+	if rankA != rankB {
+		return rankA < rankB
+	}
+
+	if s.policy == PolicyRecent {
+		return a.state.playingSince.After(b.state.playingSince)
+	}
+	// candidates are in bus order, the earlier one stays
+	return false
+}
+
+// rank is the position in the preferred list, players that are not preferred come last.
+func (s *Selector) rank(c candidate) int {
+	for i, pattern := range s.preferred {
+		if s.matches(c, pattern) {
+			return i
+		}
+	}
+	return len(s.preferred)
+}
+
+func (s *Selector) matches(c candidate, pattern string) bool {
+	name := strings.ToLower(strings.TrimPrefix(c.player, playerPrefix))
+	pattern = strings.ToLower(pattern)
+
+	// instances are exported as e.g. org.mpris.MediaPlayer2.firefox.instance_1_42
+	return name == pattern || strings.HasPrefix(name, pattern+".") || strings.ToLower(c.state.identity) == pattern
+}
+
+func (s *Selector) candidates() []candidate {
+	s.watcher.mutex.Lock()
+	defer s.watcher.mutex.Unlock()
+
+	candidates := []candidate{}
+	for _, player := range s.watcher.order {
+		state := s.watcher.players[player]
+		if !state.loaded {
+			continue
+		}
+
+		c := candidate{player: player, state: *state}
+		if slices.ContainsFunc(s.ignored, func(pattern string) bool { return s.matches(c, pattern) }) {
+			continue
+		}
+
+		playing := state.playing
+		playing.Position = state.position(time.Now())
+		c.playing = &playing
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
@@ -22,12 +22,22 @@ const (
 )
 
 type CurrentlyPlaying struct {
-	Title    string
-	Artist   []string
-	Album    string
-	Duration time.Duration
-	Position time.Duration
-	Status   PlayStatus
-	Shuffle  bool
-	Loop     LoopType
+	Title       string
+	Artist      []string
+	AlbumArtist []string
+	Album       string
+	TrackNumber int
+	Genre       []string
+	URL         string
+	ArtURL      string
+	// UserRating is between 0 and 1.
+	UserRating float64
+	Duration   time.Duration
+	Position   time.Duration
+	Status     PlayStatus
+	Shuffle    bool
+	Loop       LoopType
+	// Volume is between 0 and 1, players may go above 1 to amplify.
+	Volume float64
+	Rate   float64
 }
@@ -0,0 +1,386 @@
+package mpris
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	playerPrefix     = "org.mpris.MediaPlayer2."
+	playerPath       = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	playerInterface  = "org.mpris.MediaPlayer2.Player"
+	nameOwnerChanged = "org.freedesktop.DBus.NameOwnerChanged"
+	propertyChanged  = "org.freedesktop.DBus.Properties.PropertiesChanged"
+	seeked           = "org.mpris.MediaPlayer2.Player.Seeked"
+)
+
+// players that just appeared on the bus often have not exported their objects yet
+const playerLoadDelay = 500 * time.Millisecond
+
+type playerState struct {
+	playing  CurrentlyPlaying
+	identity string
+	rate     float64
+	// playingSince is when the player last started playing.
+	playingSince time.Time
+	// positionAt is when playing.Position was measured.
+	positionAt time.Time
+	loaded     bool
+}
+
+func (s *playerState) setStatus(status PlayStatus, now time.Time) {
+	if status == Playing && (s.playing.Status != Playing || !s.loaded) {
+		s.playingSince = now
+	}
+	s.playing.Status = status
+}
+
+// position extrapolates the position from the last measurement using the playback rate.
+func (s *playerState) position(now time.Time) time.Duration {
+	position := s.playing.Position
+	if s.playing.Status == Playing {
+		position += time.Duration(float64(now.Sub(s.positionAt)) * s.rate)
+	}
+
+	if s.playing.Duration > 0 {
+		position = min(position, s.playing.Duration)
+	}
+	return max(position, 0)
+}
+
+// Watcher keeps the state of every MPRIS player up to date by listening to D-Bus signals instead
+// of polling, so any number of modules can read it every tick without D-Bus round-trips.
+type Watcher struct {
+	dbus *DBUSInterface
+
+	players map[string]*playerState
+	// order is the order the players appeared in, like ListNames.
+	order []string
+	// owners maps unique connection names to player names, signals are sent from the unique name.
+	owners map[string]string
+	mutex  sync.Mutex
+
+	once sync.Once
+	err  error
+}
+
+func NewWatcher() *Watcher {
+	return &Watcher{
+		dbus:    &DBUSInterface{},
+		players: map[string]*playerState{},
+		order:   []string{},
+		owners:  map[string]string{},
+	}
+}
+
+// DBus returns the interface to control players, it shares the connection of the watcher.
+func (w *Watcher) DBus() *DBUSInterface {
+	return w.dbus
+}
+
+// Start connects to the session bus and loads the players, it only does so once so every module
+// sharing the watcher can call it from Init.
+func (w *Watcher) Start() error {
+	w.once.Do(func() {
+		w.err = w.start()
+	})
+	return w.err
+}
+
+func (w *Watcher) start() error {
+	err := w.dbus.Connect()
+	if err != nil {
+		return err
+	}
+
+	conn := w.dbus.session
+	matches := [][]dbus.MatchOption{
+		{
+			dbus.WithMatchSender("org.freedesktop.DBus"),
+			dbus.WithMatchInterface("org.freedesktop.DBus"),
+			dbus.WithMatchMember("NameOwnerChanged"),
+			dbus.WithMatchArg0Namespace("org.mpris.MediaPlayer2"),
+		},
+		{
+			dbus.WithMatchObjectPath(playerPath),
+			dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+			dbus.WithMatchMember("PropertiesChanged"),
+		},
+		{
+			dbus.WithMatchObjectPath(playerPath),
+			dbus.WithMatchInterface(playerInterface),
+			dbus.WithMatchMember("Seeked"),
+		},
+	}
+	for _, match := range matches {
+		err = conn.AddMatchSignal(match...)
+		if err != nil {
+			log.Printf("Failed to subscribe to MPRIS signals: %v", err)
+			return err
+		}
+	}
+
+	signals := make(chan *dbus.Signal, 64)
+	conn.Signal(signals)
+
+	players, err := w.dbus.LoadPlayers()
+	if err != nil {
+		return err
+	}
+	for _, player := range players {
+		w.addPlayer(player, w.nameOwner(player))
+	}
+
+	go w.run(signals)
+	return nil
+}
+
+// Players returns the loaded players in the order they appeared.
+func (w *Watcher) Players() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	players := []string{}
+	for _, player := range w.order {
+		if w.players[player].loaded {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// Identity returns the friendly name of player, e.g. "Spotify", falling back to the bus name.
+func (w *Watcher) Identity(player string) string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if state, ok := w.players[player]; ok && state.identity != "" {
+		return state.identity
+	}
+	return strings.TrimPrefix(player, playerPrefix)
+}
+
+// CurrentlyPlaying returns the cached state of player with the position extrapolated to now.
+func (w *Watcher) CurrentlyPlaying(player string) (*CurrentlyPlaying, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	state, ok := w.players[player]
+	if !ok || !state.loaded {
+		return nil, false
+	}
+
+	playing := state.playing
+	playing.Position = state.position(time.Now())
+	return &playing, true
+}
+
+func (w *Watcher) run(signals chan *dbus.Signal) {
+	for signal := range signals {
+		switch signal.Name {
+		case nameOwnerChanged:
+			w.handleNameOwnerChanged(signal)
+		case propertyChanged:
+			w.handlePropertiesChanged(signal)
+		case seeked:
+			w.handleSeeked(signal)
+		}
+	}
+}
+
+func (w *Watcher) handleNameOwnerChanged(signal *dbus.Signal) {
+	var name, oldOwner, newOwner string
+	err := dbus.Store(signal.Body, &name, &oldOwner, &newOwner)
+	if err != nil || !strings.HasPrefix(name, playerPrefix) {
+		return
+	}
+
+	if newOwner == "" {
+		w.removePlayer(name)
+		return
+	}
+
+	w.addPlayer(name, newOwner)
+}
+
+func (w *Watcher) handlePropertiesChanged(signal *dbus.Signal) {
+	var iface string
+	var changed map[string]dbus.Variant
+	var invalidated []string
+	err := dbus.Store(signal.Body, &iface, &changed, &invalidated)
+	if err != nil || iface != playerInterface {
+		return
+	}
+
+	w.mutex.Lock()
+	player, ok := w.owners[signal.Sender]
+	state := w.players[player]
+	if !ok || state == nil {
+		w.mutex.Unlock()
+		return
+	}
+
+	if !state.loaded || len(invalidated) > 0 {
+		w.mutex.Unlock()
+		w.loadPlayer(player)
+		return
+	}
+
+	// anchor the position before the status or rate changes how it advances
+	now := time.Now()
+	state.playing.Position = state.position(now)
+	state.positionAt = now
+
+	refreshPosition := false
+	for property, value := range changed {
+		switch property {
+		case "Metadata":
+			if metadata, ok := value.Value().(map[string]dbus.Variant); ok {
+				w.dbus.readMetadata(player, metadata, &state.playing)
+				refreshPosition = true
+			}
+		case "PlaybackStatus":
+			if status, ok := value.Value().(string); ok {
+				state.setStatus(w.dbus.stringToStatus(status), now)
+				refreshPosition = true
+			}
+		case "Rate":
+			if rate, ok := value.Value().(float64); ok {
+				state.rate = rate
+				state.playing.Rate = rate
+			}
+		case "Volume":
+			if volume, ok := value.Value().(float64); ok {
+				state.playing.Volume = volume
+			}
+		case "Shuffle":
+			if shuffle, ok := value.Value().(bool); ok {
+				state.playing.Shuffle = shuffle
+			}
+		case "LoopStatus":
+			if loop, ok := value.Value().(string); ok {
+				state.playing.Loop = w.dbus.stringToLoopType(loop)
+			}
+		}
+	}
+	w.mutex.Unlock()
+
+	// Position is not announced through PropertiesChanged, a new track or status may have moved it
+	if refreshPosition {
+		w.refreshPosition(player)
+	}
+}
+
+func (w *Watcher) handleSeeked(signal *dbus.Signal) {
+	var position int64
+	err := dbus.Store(signal.Body, &position)
+	if err != nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if state, ok := w.players[w.owners[signal.Sender]]; ok {
+		state.playing.Position = time.Duration(position) * time.Microsecond
+		state.positionAt = time.Now()
+	}
+}
+
+func (w *Watcher) addPlayer(player string, owner string) {
+	w.mutex.Lock()
+	for unique, name := range w.owners {
+		if name == player {
+			delete(w.owners, unique)
+		}
+	}
+	if owner != "" {
+		w.owners[owner] = player
+	}
+	if _, ok := w.players[player]; !ok {
+		w.players[player] = &playerState{rate: 1}
+		w.order = append(w.order, player)
+	}
+	w.mutex.Unlock()
+
+	if !w.loadPlayer(player) {
+		go func() {
+			time.Sleep(playerLoadDelay)
+			w.loadPlayer(player)
+		}()
+	}
+}
+
+func (w *Watcher) removePlayer(player string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	delete(w.players, player)
+	w.order = slices.DeleteFunc(w.order, func(name string) bool {
+		return name == player
+	})
+	for unique, name := range w.owners {
+		if name == player {
+			delete(w.owners, unique)
+		}
+	}
+}
+
+// loadPlayer reads the full state of a player, ok is false if it could not be read (yet).
+func (w *Watcher) loadPlayer(player string) bool {
+	playing, err := w.dbus.LoadCurrentlyPlaying(player)
+	if err != nil {
+		return false
+	}
+
+	identity := ""
+	variant, err := w.dbus.session.Object(player, playerPath).GetProperty("org.mpris.MediaPlayer2.Identity")
+	if err == nil {
+		identity, _ = variant.Value().(string)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if state, ok := w.players[player]; ok {
+		now := time.Now()
+		status := playing.Status
+		playing.Status = state.playing.Status
+		state.playing = *playing
+		state.setStatus(status, now)
+		state.identity = identity
+		state.rate = playing.Rate
+		state.positionAt = now
+		state.loaded = true
+	}
+	return true
+}
+
+func (w *Watcher) refreshPosition(player string) {
+	position, err := w.dbus.LoadPosition(player)
+	if err != nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if state, ok := w.players[player]; ok {
+		state.playing.Position = position
+		state.positionAt = time.Now()
+	}
+}
+
+func (w *Watcher) nameOwner(player string) string {
+	var owner string
+	err := w.dbus.session.BusObject().Call("org.freedesktop.DBus.GetNameOwner", 0, player).Store(&owner)
+	if err != nil {
+		log.Printf("Failed to get owner of %s: %v", player, err)
+	}
+	return owner
+}
@@ -0,0 +1,98 @@
+package chatbox
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	// ProgressClassic draws |xxxx----|.
+	ProgressClassic = "classic"
+	// ProgressBlocks draws ████▌░░░ with eighth block precision.
+	ProgressBlocks = "blocks"
+	// ProgressEmoji draws 🟩🟩🟩⬜⬜.
+	ProgressEmoji = "emoji"
+	// ProgressLine draws ━━━━●────.
+	ProgressLine = "line"
+	// ProgressPercent only shows the percentage, e.g. 42%.
+	ProgressPercent = "percent"
+)
+
+var ProgressStyles = []string{ProgressClassic, ProgressBlocks, ProgressEmoji, ProgressLine, ProgressPercent}
+
+const DefaultMarqueeSpeed = 2
+
+// marqueeSeparator is shown between the end and the restart of scrolling text.
+const marqueeSeparator = "   "
+
+var partialBlocks = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+func IsProgressStyle(style string) bool {
+	return slices.Contains(ProgressStyles, style)
+}
+
+// ProgressBar draws a bar width characters wide, ratio is clamped to 0..1.
+func ProgressBar(ratio float64, width int, style string) string {
+	if math.IsNaN(ratio) {
+		ratio = 0
+	}
+	ratio = min(max(ratio, 0), 1)
+	width = max(width, 1)
+	filled := int(ratio * float64(width))
+
+	switch style {
+	case ProgressBlocks:
+		eighths := int(ratio*float64(width)*8) % 8
+		bar := strings.Repeat("█", filled)
+		if filled < width {
+			bar += partialBlocks[eighths]
+			if eighths > 0 {
+				filled++
+			}
+		}
+		return bar + strings.Repeat("░", width-filled)
+	case ProgressEmoji:
+		return strings.Repeat("🟩", filled) + strings.Repeat("⬜", width-filled)
+	case ProgressLine:
+		knob := min(filled, width-1)
+		return strings.Repeat("━", knob) + "●" + strings.Repeat("─", width-knob-1)
+	case ProgressPercent:
+		return fmt.Sprintf("%d%%", int(ratio*100))
+	default:
+		return "|" + strings.Repeat("x", filled) + strings.Repeat("-", width-filled) + "|"
+	}
+}
+
+// Marquee scrolls s through a window of width grapheme clusters, advancing speed characters per
+// second. Text that fits is returned unchanged. The position only depends on now, so the text keeps
+// scrolling at the same pace regardless of how often the chatbox is rendered.
+func Marquee(s string, width int, speed int, now time.Time) string {
+	if width <= 0 || Length(s) <= width {
+		return s
+	}
+
+	clusters := graphemes(s + marqueeSeparator)
+	offset := int(now.UnixMilli()*int64(max(speed, 1))/1000) % len(clusters)
+
+	out := &strings.Builder{}
+	for i := 0; i < width; i++ {
+		out.WriteString(clusters[(offset+i)%len(clusters)])
+	}
+	return out.String()
+}
+
+func graphemes(s string) []string {
+	clusters := []string{}
+	state := -1
+	for s != "" {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
@@ -0,0 +1,169 @@
+package chatbox
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// VRChat cuts the chatbox input after 144 characters and 9 lines.
+const (
+	DefaultMaxCharacters = 144
+	DefaultMaxLines      = 9
+)
+
+// Lines are only truncated if at least this many characters stay visible, otherwise they get dropped.
+const minTruncatedLength = 10
+
+// Length returns the number of user-perceived characters (grapheme clusters) in s, an emoji with
+// modifiers counts as one.
+func Length(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// Truncate shortens s to at most width grapheme clusters, including the suffix.
+func Truncate(s string, width int, suffix string) string {
+	if Length(s) <= width {
+		return s
+	}
+
+	keep := max(width-Length(suffix), 0)
+
+	out := &strings.Builder{}
+	state := -1
+	rest := s
+	for i := 0; i < keep && rest != ""; i++ {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		out.WriteString(cluster)
+	}
+
+	return out.String() + suffix
+}
+
+type renderedLine struct {
+	index    int
+	priority int
+	text     string
+}
+
+func totalLength(lines []renderedLine) int {
+	if len(lines) == 0 {
+		return 0
+	}
+
+	// the lines are joined by newlines
+	length := len(lines) - 1
+	for _, line := range lines {
+		length += Length(line.text)
+	}
+	return length
+}
+
+func (c *ChatBoxBuilder) fits(lines []renderedLine) bool {
+	return len(lines) <= c.maxLines && totalLength(lines) <= c.maxCharacters
+}
+
+// rotationStep is how far the rotation advances once a paginated chatbox was sent, pool is the
+// number of rotating lines and 0 if everything fits.
+type rotationStep struct {
+	shown int
+	pool  int
+}
+
+// budget reduces lines to the configured character and line limits, lowest priority lines are
+// dropped or truncated first. Lines of equal priority are dropped from the bottom up. It does not
+// change the rotation, so previews and flushes between ticks show the same lines.
+func (c *ChatBoxBuilder) budget(lines []renderedLine) ([]renderedLine, rotationStep) {
+	if c.fits(lines) {
+		return lines, rotationStep{}
+	}
+
+	byPriority := slices.Clone(lines)
+	slices.SortStableFunc(byPriority, func(a renderedLine, b renderedLine) int {
+		return b.priority - a.priority
+	})
+
+	step := rotationStep{}
+	if c.paginate {
+		byPriority, step = c.page(byPriority)
+	} else {
+		byPriority = c.truncate(byPriority)
+	}
+
+	slices.SortFunc(byPriority, func(a renderedLine, b renderedLine) int {
+		return a.index - b.index
+	})
+	return byPriority, step
+}
+
+// truncate expects lines sorted by descending priority.
+func (c *ChatBoxBuilder) truncate(lines []renderedLine) []renderedLine {
+	if len(lines) > c.maxLines {
+		lines = lines[:c.maxLines]
+	}
+
+	for len(lines) > 0 {
+		excess := totalLength(lines) - c.maxCharacters
+		if excess <= 0 {
+			break
+		}
+
+		last := &lines[len(lines)-1]
+		keep := Length(last.text) - excess
+		if keep >= minTruncatedLength || (len(lines) == 1 && keep > 0) {
+			last.text = Truncate(last.text, keep, "…")
+			break
+		}
+
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// page expects lines sorted by descending priority. Lines that fit and have a higher priority than
+// the first overflowing line are shown every tick, the remaining lines rotate through the free space.
+func (c *ChatBoxBuilder) page(lines []renderedLine) ([]renderedLine, rotationStep) {
+	fitting := 0
+	for fitting < len(lines) && c.fits(lines[:fitting+1]) {
+		fitting++
+	}
+
+	cutoff := lines[fitting].priority
+	fixed := 0
+	for fixed < fitting && lines[fixed].priority > cutoff {
+		fixed++
+	}
+
+	page := slices.Clone(lines[:fixed])
+	pool := lines[fixed:]
+
+	shown := 0
+	for shown < len(pool) {
+		candidate := pool[(c.rotation+shown)%len(pool)]
+		if !c.fits(append(page, candidate)) {
+			if shown == 0 {
+				// does not fit on its own, show as much of it as possible
+				page = c.truncate(append(page, candidate))
+				shown++
+			}
+			break
+		}
+
+		page = append(page, candidate)
+		shown++
+	}
+
+	return page, rotationStep{shown: max(shown, 1), pool: len(pool)}
+}
+
+// advanceRotation moves the rotation past the lines of the last flushed page.
+func (c *ChatBoxBuilder) advanceRotation() {
+	if c.sentStep.pool == 0 {
+		c.rotation = 0
+		return
+	}
+	c.rotation = (c.rotation + c.sentStep.shown) % c.sentStep.pool
+}
@@ -1,93 +1,301 @@
 package chatbox
 
 import (
-	"fmt"
 	"log"
-	"regexp"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Glowman554/OpenOSC/locale"
 	"github.com/hypebeast/go-osc/osc"
 )
 
 type ChatBoxLine struct {
-	expectedPlaceholders []string
-	line                 string
+	template *Template
+	priority int
 }
 
-func NewChatBoxLine(line string) *ChatBoxLine {
-	re := regexp.MustCompile(`\{([^{}]+)\}`)
-	matches := re.FindAllStringSubmatch(line, -1)
-
-	var placeholders []string
-	for _, match := range matches {
-		if len(match) > 1 {
-			placeholders = append(placeholders, match[1])
-		}
+func NewChatBoxLine(line string, priority int) (*ChatBoxLine, error) {
+	template, err := ParseTemplate(line)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ChatBoxLine{
-		expectedPlaceholders: placeholders,
-		line:                 line,
-	}
+		template: template,
+		priority: priority,
+	}, nil
 }
 
-func (c *ChatBoxLine) Applies(parent *ChatBoxBuilder) bool {
-	for _, expected := range c.expectedPlaceholders {
-		if _, ok := parent.placeholders[expected]; !ok {
-			return false
-		}
+// GetLine renders the line, ok is false if the line should be left out.
+func (c *ChatBoxLine) GetLine(parent *ChatBoxBuilder) (string, bool) {
+	line, ok := c.template.Render(parent.lookup, parent.locale)
+	if !ok || strings.TrimSpace(line) == "" {
+		return "", false
 	}
-	return true
+	return line, true
 }
 
-func (c *ChatBoxLine) GetLine(parent *ChatBoxBuilder) string {
-	line := c.line
-	for _, expected := range c.expectedPlaceholders {
-		line = strings.ReplaceAll(line, "{"+expected+"}", parent.placeholders[expected])
-	}
-	return line
+// placeholderValue is either a fixed text or computed on every render. Ephemeral values are removed
+// when they are not set again during a tick, sticky ones persist until they expire.
+type placeholderValue struct {
+	text    string
+	compute func() string
+	sticky  bool
+	// expires is only used for sticky values, zero never expires.
+	expires time.Time
+}
+
+func (v placeholderValue) expired(now time.Time) bool {
+	return v.sticky && !v.expires.IsZero() && !now.Before(v.expires)
 }
 
 type ChatBoxBuilder struct {
-	lines        []*ChatBoxLine
-	placeholders map[string]string
+	pages        []*ChatBoxPage
+	placeholders map[string]placeholderValue
+	updated      map[string]bool
+	locale       *locale.Locale
+
+	currentPage int
+	shownPage   int
+	pageStarted time.Time
+	messages    []*chatBoxMessage
+	scheduled   []*scheduledMessage
+	outputs     []*chatBoxOutput
+
+	maxCharacters int
+	maxLines      int
+	paginate      bool
+	rotation      int
+	// sentStep is the rotation step of the last flush, it is applied by the next tick.
+	sentStep rotationStep
+
+	defaultFlags SendFlags
+	keepAlive    time.Duration
+	limiter      *tokenBucket
+	lastSent     string
+	lastFlags    SendFlags
+	lastSentAt   time.Time
+
+	typing          map[string]bool
+	typingSent      bool
+	transitionDelay time.Duration
+	transitionUntil time.Time
+	clearRequested  bool
+	// clearedText is the text that was shown when the chatbox was cleared, it is not sent again
+	// until the content changes.
+	clearedText string
+	cleared     bool
+	closed      bool
+	sendMutex   sync.Mutex
+
+	paused   bool
+	rendered string
+	mutex    sync.Mutex
 }
 
 func NewChatBoxBuilder() *ChatBoxBuilder {
 	return &ChatBoxBuilder{
-		lines:        []*ChatBoxLine{},
-		placeholders: map[string]string{},
+		pages:         []*ChatBoxPage{},
+		shownPage:     -1,
+		placeholders:  map[string]placeholderValue{},
+		updated:       map[string]bool{},
+		locale:        locale.New("en", nil),
+		messages:      []*chatBoxMessage{},
+		scheduled:     []*scheduledMessage{},
+		outputs:       []*chatBoxOutput{},
+		maxCharacters: DefaultMaxCharacters,
+		maxLines:      DefaultMaxLines,
+		paginate:      false,
+		defaultFlags:  SendFlags{Immediate: true, Notify: false},
+		keepAlive:     DefaultKeepAlive,
+		limiter:       newTokenBucket(DefaultRateLimitBurst, DefaultRateLimitInterval),
+		typing:        map[string]bool{},
 	}
 }
 
-func (c *ChatBoxBuilder) AddLine(line string) {
-	c.lines = append(c.lines, NewChatBoxLine(line))
+// SetSendOptions configures the default flags, how often unchanged text is resent so VRChat does
+// not hide it and the token bucket rate limit protecting against VRChat's spam throttle.
+func (c *ChatBoxBuilder) SetSendOptions(flags SendFlags, keepAlive time.Duration, burst int, interval time.Duration) {
+	c.defaultFlags = flags
+	c.keepAlive = keepAlive
+	c.limiter = newTokenBucket(burst, interval)
+}
+
+// SetLocale sets the locale used to translate fallback texts and format numbers.
+func (c *ChatBoxBuilder) SetLocale(locale *locale.Locale) {
+	c.locale = locale
+}
+
+// SetLimits configures the character and line budget, with paginate lines that do not fit are
+// rotated through on successive ticks instead of being dropped.
+func (c *ChatBoxBuilder) SetLimits(maxCharacters int, maxLines int, paginate bool) {
+	c.maxCharacters = maxCharacters
+	c.maxLines = maxLines
+	c.paginate = paginate
 }
 
+// AddLine adds a line to the last page, creating a default page if there is none.
+func (c *ChatBoxBuilder) AddLine(line string, priority int) error {
+	if len(c.pages) == 0 {
+		c.AddPage("default", DefaultPageDuration)
+	}
+	return c.pages[len(c.pages)-1].AddLine(line, priority)
+}
+
+// BeginTick starts a module tick, ephemeral placeholders that are not set again before EndTick are
+// removed. The previous values stay visible to Flush until then.
 func (c *ChatBoxBuilder) BeginTick() {
-	for k := range c.placeholders {
-		delete(c.placeholders, k)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k := range c.updated {
+		delete(c.updated, k)
+	}
+}
+
+func (c *ChatBoxBuilder) Render() string {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	lines, _ := c.current(time.Now())
+	chatbox, _ := c.renderLines(lines)
+	return chatbox
+}
+
+// RenderPage renders a page regardless of the rotation and pending messages.
+func (c *ChatBoxBuilder) RenderPage(page *ChatBoxPage) string {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	chatbox, _ := c.renderLines(page.lines)
+	return chatbox
+}
+
+func (c *ChatBoxBuilder) Pages() []*ChatBoxPage {
+	return c.pages
+}
+
+func (c *ChatBoxBuilder) renderLines(chatboxLines []*ChatBoxLine) (string, rotationStep) {
+	lines := []renderedLine{}
+
+	for i, line := range chatboxLines {
+		if text, ok := line.GetLine(c); ok {
+			lines = append(lines, renderedLine{index: i, priority: line.priority, text: text})
+		}
 	}
+
+	budgeted, step := c.budget(lines)
+	texts := []string{}
+	for _, line := range budgeted {
+		texts = append(texts, line.text)
+	}
+
+	return strings.Join(texts, "\n"), step
 }
 
-func (c *ChatBoxBuilder) EndTick(client *osc.Client, debug bool) error {
-	chatbox := ""
+// EndTick removes the ephemeral placeholders that were not set during the tick as well as expired
+// sticky ones and flushes the chatbox.
+func (c *ChatBoxBuilder) EndTick(client *osc.Client) error {
+	now := time.Now()
 
-	for _, line := range c.lines {
-		if line.Applies(c) {
-			chatbox += line.GetLine(c) + "\n"
+	c.mutex.Lock()
+	for name, value := range c.placeholders {
+		if (!value.sticky && !c.updated[name]) || value.expired(now) {
+			delete(c.placeholders, name)
 		}
 	}
+	c.mutex.Unlock()
+
+	// overflowing lines rotate once per tick, not on every flush
+	c.sendMutex.Lock()
+	c.advanceRotation()
+	c.sendMutex.Unlock()
+
+	return c.Flush(client)
+}
 
-	if debug {
-		fmt.Println("\033[2J\033[H")
-		fmt.Print(chatbox)
+// Flush renders the chatbox and sends it if it changed. It may be called more often than the
+// module tick, e.g. to animate marquees and progress bars between ticks.
+func (c *ChatBoxBuilder) Flush(client *osc.Client) error {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	if c.closed {
+		return nil
 	}
 
+	now := time.Now()
+	lines, flags := c.current(now)
+	chatbox, step := c.renderLines(lines)
+	c.sentStep = step
+
+	c.mutex.Lock()
+	c.rendered = chatbox
+	paused := c.paused
+	clearRequested := c.clearRequested
+	c.clearRequested = false
+	if now.Before(c.transitionUntil) {
+		c.typing["transition"] = true
+	} else {
+		delete(c.typing, "transition")
+	}
+	transition := c.typing["transition"]
+	c.mutex.Unlock()
+
+	c.writeOutputs(chatbox)
+	c.updateTyping(client)
+
+	if paused {
+		return nil
+	}
+
+	if clearRequested {
+		err := c.sendText(client, "", flags, now)
+		if err != nil {
+			return err
+		}
+		c.cleared = true
+		c.clearedText = chatbox
+		return nil
+	}
+
+	if c.cleared {
+		if chatbox == c.clearedText {
+			return nil
+		}
+		c.cleared = false
+	}
+
+	// keep the previous page visible while the typing indicator announces the next one
+	if transition {
+		return nil
+	}
+
+	return c.send(client, chatbox, flags, now)
+}
+
+// send skips text that was already sent unless the keep-alive is due and drops sends exceeding
+// the rate limit, they are retried on the next tick since the text still differs. An empty text
+// clears the chatbox once.
+func (c *ChatBoxBuilder) send(client *osc.Client, chatbox string, flags SendFlags, now time.Time) error {
+	if chatbox == c.lastSent && flags == c.lastFlags {
+		if chatbox == "" || now.Sub(c.lastSentAt) < c.keepAlive {
+			return nil
+		}
+	}
+
+	if !c.limiter.take(now) {
+		return nil
+	}
+
+	return c.sendText(client, chatbox, flags, now)
+}
+
+func (c *ChatBoxBuilder) sendText(client *osc.Client, chatbox string, flags SendFlags, now time.Time) error {
 	msg := osc.NewMessage("/chatbox/input")
 	msg.Append(chatbox)
-	msg.Append(true)
-	msg.Append(false)
+	msg.Append(flags.Immediate)
+	msg.Append(flags.Notify)
 
 	err := client.Send(msg)
 	if err != nil {
@@ -95,9 +303,85 @@ func (c *ChatBoxBuilder) EndTick(client *osc.Client, debug bool) error {
 		return err
 	}
 
+	c.lastSent = chatbox
+	c.lastFlags = flags
+	c.lastSentAt = now
 	return nil
 }
 
+// Placeholder sets an ephemeral placeholder, it has to be set again every tick.
 func (c *ChatBoxBuilder) Placeholder(placeholder string, text string) {
-	c.placeholders[placeholder] = text
+	c.setPlaceholder(placeholder, placeholderValue{text: text})
+}
+
+// PlaceholderFunc sets an ephemeral placeholder that is computed on every render, e.g. a progress
+// bar that keeps moving between module ticks.
+func (c *ChatBoxBuilder) PlaceholderFunc(placeholder string, value func() string) {
+	c.setPlaceholder(placeholder, placeholderValue{compute: value})
+}
+
+// StickyPlaceholder sets a placeholder that persists across ticks until ttl passes without it being
+// set again, a ttl of 0 keeps it until it is removed. Meant for values measured asynchronously or
+// less often than every tick.
+func (c *ChatBoxBuilder) StickyPlaceholder(placeholder string, text string, ttl time.Duration) {
+	c.setPlaceholder(placeholder, sticky(placeholderValue{text: text}, ttl))
+}
+
+func (c *ChatBoxBuilder) StickyPlaceholderFunc(placeholder string, value func() string, ttl time.Duration) {
+	c.setPlaceholder(placeholder, sticky(placeholderValue{compute: value}, ttl))
+}
+
+func (c *ChatBoxBuilder) RemovePlaceholder(placeholder string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.placeholders, placeholder)
+}
+
+func sticky(value placeholderValue, ttl time.Duration) placeholderValue {
+	value.sticky = true
+	if ttl > 0 {
+		value.expires = time.Now().Add(ttl)
+	}
+	return value
+}
+
+func (c *ChatBoxBuilder) setPlaceholder(placeholder string, value placeholderValue) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.placeholders[placeholder] = value
+	c.updated[placeholder] = true
+}
+
+func (c *ChatBoxBuilder) lookup(placeholder string) (string, bool) {
+	c.mutex.Lock()
+	value, ok := c.placeholders[placeholder]
+	c.mutex.Unlock()
+
+	if !ok || value.expired(time.Now()) {
+		return "", false
+	}
+	if value.compute != nil {
+		return value.compute(), true
+	}
+	return value.text, true
+}
+
+// Rendered returns the chatbox text of the last tick.
+func (c *ChatBoxBuilder) Rendered() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.rendered
+}
+
+// SetPaused stops (or resumes) sending the chatbox to VRChat, rendering and other outputs continue.
+func (c *ChatBoxBuilder) SetPaused(paused bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.paused = paused
+}
+
+func (c *ChatBoxBuilder) Paused() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.paused
 }
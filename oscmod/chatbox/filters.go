@@ -0,0 +1,183 @@
+package chatbox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type filter struct {
+	minArgs int
+	maxArgs int
+	// numeric lists the argument indices that have to be integers.
+	numeric  []int
+	validate func(args []string) error
+	apply    func(ctx *renderContext, value string, args []string) string
+}
+
+func (f *filter) check(args []string) error {
+	if len(args) < f.minArgs || len(args) > f.maxArgs {
+		if f.minArgs == f.maxArgs {
+			return fmt.Errorf("expected %d arguments, got %d", f.minArgs, len(args))
+		}
+		return fmt.Errorf("expected %d to %d arguments, got %d", f.minArgs, f.maxArgs, len(args))
+	}
+
+	for _, index := range f.numeric {
+		if index < len(args) {
+			if _, err := strconv.Atoi(args[index]); err != nil {
+				return fmt.Errorf("argument %d (%q) is not a number", index+1, args[index])
+			}
+		}
+	}
+
+	if f.validate != nil {
+		return f.validate(args)
+	}
+
+	return nil
+}
+
+var filters = map[string]*filter{}
+
+func init() {
+	filters["upper"] = &filter{apply: func(ctx *renderContext, value string, args []string) string {
+		return strings.ToUpper(value)
+	}}
+
+	filters["lower"] = &filter{apply: func(ctx *renderContext, value string, args []string) string {
+		return strings.ToLower(value)
+	}}
+
+	filters["trim"] = &filter{apply: func(ctx *renderContext, value string, args []string) string {
+		return strings.TrimSpace(value)
+	}}
+
+	// truncate:width[:suffix]
+	filters["truncate"] = &filter{minArgs: 1, maxArgs: 2, numeric: []int{0}, apply: filterTruncate}
+
+	// pad:width[:left|right|center]
+	filters["pad"] = &filter{minArgs: 1, maxArgs: 2, numeric: []int{0}, validate: validatePad, apply: filterPad}
+
+	// number[:decimals]
+	filters["number"] = &filter{minArgs: 0, maxArgs: 1, numeric: []int{0}, apply: filterNumber}
+
+	// marquee:width[:speed] scrolls text longer than width, speed is in characters per second
+	filters["marquee"] = &filter{minArgs: 1, maxArgs: 2, numeric: []int{0, 1}, apply: filterMarquee}
+
+	// bar[:width[:style]] draws a progress bar for a percentage
+	filters["bar"] = &filter{minArgs: 0, maxArgs: 2, numeric: []int{0}, validate: validateBar, apply: filterBar}
+
+	// replace:old:new
+	filters["replace"] = &filter{minArgs: 2, maxArgs: 2, apply: func(ctx *renderContext, value string, args []string) string {
+		return strings.ReplaceAll(value, args[0], args[1])
+	}}
+}
+
+func filterTruncate(ctx *renderContext, value string, args []string) string {
+	width, _ := strconv.Atoi(args[0])
+	suffix := "…"
+	if len(args) > 1 {
+		suffix = args[1]
+	}
+
+	return Truncate(value, width, suffix)
+}
+
+func validatePad(args []string) error {
+	if len(args) > 1 {
+		switch args[1] {
+		case "left", "right", "center":
+		default:
+			return fmt.Errorf("invalid alignment %q", args[1])
+		}
+	}
+	return nil
+}
+
+func filterPad(ctx *renderContext, value string, args []string) string {
+	width, _ := strconv.Atoi(args[0])
+	align := "left"
+	if len(args) > 1 {
+		align = args[1]
+	}
+
+	missing := width - Length(value)
+	if missing <= 0 {
+		return value
+	}
+
+	switch align {
+	case "right":
+		return strings.Repeat(" ", missing) + value
+	case "center":
+		return strings.Repeat(" ", missing/2) + value + strings.Repeat(" ", missing-missing/2)
+	default:
+		return value + strings.Repeat(" ", missing)
+	}
+}
+
+// filterNumber reformats the leading number of value, any suffix like "%" or "MB" is kept.
+func filterNumber(ctx *renderContext, value string, args []string) string {
+	decimals := 0
+	if len(args) > 0 {
+		decimals, _ = strconv.Atoi(args[0])
+	}
+
+	number, suffix, ok := leadingNumber(value)
+	if !ok {
+		return value
+	}
+
+	return ctx.locale.FormatNumber(number, decimals) + suffix
+}
+
+func validateBar(args []string) error {
+	if len(args) > 1 && !IsProgressStyle(args[1]) {
+		return fmt.Errorf("invalid style %q (available: %s)", args[1], strings.Join(ProgressStyles, ", "))
+	}
+	return nil
+}
+
+// filterBar draws a progress bar for a percentage like "42" or "42%".
+func filterBar(ctx *renderContext, value string, args []string) string {
+	width := 10
+	if len(args) > 0 {
+		width, _ = strconv.Atoi(args[0])
+	}
+	style := ProgressClassic
+	if len(args) > 1 {
+		style = args[1]
+	}
+
+	percent, _, ok := leadingNumber(value)
+	if !ok {
+		return value
+	}
+
+	return ProgressBar(percent/100, width, style)
+}
+
+func filterMarquee(ctx *renderContext, value string, args []string) string {
+	width, _ := strconv.Atoi(args[0])
+	speed := DefaultMarqueeSpeed
+	if len(args) > 1 {
+		speed, _ = strconv.Atoi(args[1])
+	}
+
+	return Marquee(value, width, speed, ctx.now)
+}
+
+// leadingNumber parses the number at the start of value and returns the rest as suffix.
+func leadingNumber(value string) (float64, string, bool) {
+	end := 0
+	for end < len(value) && strings.ContainsRune("+-0123456789.", rune(value[end])) {
+		end++
+	}
+
+	number, err := strconv.ParseFloat(value[:end], 64)
+	if err != nil {
+		return 0, value, false
+	}
+	return number, value[end:], true
+}
@@ -0,0 +1,78 @@
+package chatbox
+
+import (
+	"log"
+	"strings"
+)
+
+// Output receives the rendered text on every flush next to VRChat, e.g. a text file or an OBS
+// text source. Outputs should skip unchanged text themselves and must not block.
+type Output interface {
+	Name() string
+	Write(text string) error
+	Close() error
+}
+
+type chatBoxOutput struct {
+	output Output
+	// lines replace the chatbox text if set.
+	lines []*ChatBoxLine
+}
+
+// AddOutput adds an output, with lines it gets its own template instead of the chatbox text. Its
+// lines are not budgeted since the VRChat limits do not apply.
+func (c *ChatBoxBuilder) AddOutput(output Output, lines []string) error {
+	chatboxOutput := &chatBoxOutput{
+		output: output,
+		lines:  []*ChatBoxLine{},
+	}
+
+	for _, line := range lines {
+		parsed, err := NewChatBoxLine(line, 0)
+		if err != nil {
+			return err
+		}
+		chatboxOutput.lines = append(chatboxOutput.lines, parsed)
+	}
+
+	c.outputs = append(c.outputs, chatboxOutput)
+	return nil
+}
+
+func (c *ChatBoxBuilder) writeOutputs(chatbox string) {
+	for _, output := range c.outputs {
+		text := chatbox
+		if len(output.lines) > 0 {
+			text = c.renderUnbudgeted(output.lines)
+		}
+
+		err := output.output.Write(text)
+		if err != nil {
+			log.Printf("Failed to write to %s: %v", output.output.Name(), err)
+		}
+	}
+}
+
+func (c *ChatBoxBuilder) closeOutputs() {
+	for _, output := range c.outputs {
+		err := output.output.Write("")
+		if err != nil {
+			log.Printf("Failed to clear %s: %v", output.output.Name(), err)
+		}
+
+		err = output.output.Close()
+		if err != nil {
+			log.Printf("Failed to close %s: %v", output.output.Name(), err)
+		}
+	}
+}
+
+func (c *ChatBoxBuilder) renderUnbudgeted(chatboxLines []*ChatBoxLine) string {
+	texts := []string{}
+	for _, line := range chatboxLines {
+		if text, ok := line.GetLine(c); ok {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
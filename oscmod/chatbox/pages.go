@@ -0,0 +1,209 @@
+package chatbox
+
+import (
+	"strings"
+	"time"
+)
+
+const DefaultPageDuration = 10 * time.Second
+
+// SendFlags are the flags sent along with /chatbox/input.
+type SendFlags struct {
+	// Immediate sends the text right away instead of opening the keyboard.
+	Immediate bool
+	// Notify plays the chatbox notification sound.
+	Notify bool
+}
+
+type ChatBoxPage struct {
+	name     string
+	lines    []*ChatBoxLine
+	duration time.Duration
+	flags    *SendFlags
+}
+
+func (p *ChatBoxPage) Name() string {
+	return p.name
+}
+
+// SetFlags overrides the default send flags while the page is shown.
+func (p *ChatBoxPage) SetFlags(flags SendFlags) {
+	p.flags = &flags
+}
+
+// AddLine adds a line to the page, lines with a higher priority are kept when the text is over budget.
+func (p *ChatBoxPage) AddLine(line string, priority int) error {
+	parsed, err := NewChatBoxLine(line, priority)
+	if err != nil {
+		return err
+	}
+
+	p.lines = append(p.lines, parsed)
+	return nil
+}
+
+type chatBoxMessage struct {
+	lines    []*ChatBoxLine
+	duration time.Duration
+	flags    *SendFlags
+	until    time.Time
+}
+
+type scheduledMessage struct {
+	message  *chatBoxMessage
+	interval time.Duration
+	next     time.Time
+}
+
+func newChatBoxMessage(text string, duration time.Duration, flags *SendFlags) (*chatBoxMessage, error) {
+	message := &chatBoxMessage{
+		lines:    []*ChatBoxLine{},
+		duration: duration,
+		flags:    flags,
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		parsed, err := NewChatBoxLine(line, 0)
+		if err != nil {
+			return nil, err
+		}
+		message.lines = append(message.lines, parsed)
+	}
+
+	return message, nil
+}
+
+// AddPage adds a page to the rotation, every page is shown for its duration before the next one.
+func (c *ChatBoxBuilder) AddPage(name string, duration time.Duration) *ChatBoxPage {
+	if duration <= 0 {
+		duration = DefaultPageDuration
+	}
+
+	page := &ChatBoxPage{
+		name:     name,
+		lines:    []*ChatBoxLine{},
+		duration: duration,
+	}
+	c.pages = append(c.pages, page)
+	return page
+}
+
+// Message shows text for duration, preempting the page rotation. Messages are queued if another
+// one is still showing. The text is a template and may contain multiple lines, flags may be nil to
+// use the default send flags.
+func (c *ChatBoxBuilder) Message(text string, duration time.Duration, flags *SendFlags) error {
+	message, err := newChatBoxMessage(text, duration, flags)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.messages = append(c.messages, message)
+	return nil
+}
+
+// QueuedMessages returns the number of one-shot messages that are shown or waiting to be shown.
+func (c *ChatBoxBuilder) QueuedMessages() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.messages)
+}
+
+// Schedule shows text for duration every interval, the first time after one interval.
+func (c *ChatBoxBuilder) Schedule(text string, interval time.Duration, duration time.Duration, flags *SendFlags) error {
+	message, err := newChatBoxMessage(text, duration, flags)
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.scheduled = append(c.scheduled, &scheduledMessage{
+		message:  message,
+		interval: interval,
+		next:     time.Now().Add(interval),
+	})
+	return nil
+}
+
+// current selects what should be shown right now: a one-shot or scheduled message if there is
+// one, otherwise the current page of the rotation.
+func (c *ChatBoxBuilder) current(now time.Time) ([]*ChatBoxLine, SendFlags) {
+	c.mutex.Lock()
+	for _, scheduled := range c.scheduled {
+		if !now.Before(scheduled.next) {
+			message := *scheduled.message
+			c.messages = append(c.messages, &message)
+			scheduled.next = now.Add(scheduled.interval)
+		}
+	}
+
+	for len(c.messages) > 0 {
+		message := c.messages[0]
+		if message.until.IsZero() {
+			message.until = now.Add(message.duration)
+		}
+
+		if now.Before(message.until) {
+			c.mutex.Unlock()
+			return message.lines, c.flagsOrDefault(message.flags)
+		}
+
+		c.messages = c.messages[1:]
+	}
+	c.mutex.Unlock()
+
+	if len(c.pages) == 0 {
+		return []*ChatBoxLine{}, c.defaultFlags
+	}
+
+	if c.pageStarted.IsZero() {
+		c.pageStarted = now
+	}
+
+	if now.Sub(c.pageStarted) >= c.pages[c.currentPage].duration {
+		c.currentPage = (c.currentPage + 1) % len(c.pages)
+		c.pageStarted = now
+	}
+
+	// skip pages without content, e.g. the now playing page while nothing is playing
+	for i := 0; i < len(c.pages); i++ {
+		index := (c.currentPage + i) % len(c.pages)
+		if c.hasContent(c.pages[index].lines) {
+			if index != c.currentPage {
+				c.currentPage = index
+				c.pageStarted = now
+			}
+			break
+		}
+	}
+
+	if c.currentPage != c.shownPage {
+		if c.shownPage >= 0 && c.transitionDelay > 0 {
+			c.mutex.Lock()
+			c.transitionUntil = now.Add(c.transitionDelay)
+			c.mutex.Unlock()
+		}
+		c.shownPage = c.currentPage
+	}
+
+	page := c.pages[c.currentPage]
+	return page.lines, c.flagsOrDefault(page.flags)
+}
+
+func (c *ChatBoxBuilder) flagsOrDefault(flags *SendFlags) SendFlags {
+	if flags == nil {
+		return c.defaultFlags
+	}
+	return *flags
+}
+
+func (c *ChatBoxBuilder) hasContent(lines []*ChatBoxLine) bool {
+	for _, line := range lines {
+		if _, ok := line.GetLine(c); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package chatbox
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PlaceholderInfo describes a placeholder provided by a module. A * in the name stands for a
+// variable part, e.g. gpuinfo.nvidia*.usage.
+type PlaceholderInfo struct {
+	Name        string
+	Description string
+	Example     string
+	// UpdateRate is how often the value changes, 0 means every tick.
+	UpdateRate time.Duration
+	// TTL is how long a sticky value is kept without being updated, 0 means the value is ephemeral
+	// and disappears as soon as a tick does not set it.
+	TTL time.Duration
+}
+
+func (p PlaceholderInfo) Matches(name string) bool {
+	if !strings.Contains(p.Name, "*") {
+		return p.Name == name
+	}
+
+	expression := strings.ReplaceAll(regexp.QuoteMeta(p.Name), `\*`, `[^.]+`)
+	return regexp.MustCompile("^" + expression + "$").MatchString(name)
+}
+
+type RegisteredPlaceholder struct {
+	PlaceholderInfo
+	Module string
+	Active bool
+}
+
+type PlaceholderRegistry struct {
+	placeholders []RegisteredPlaceholder
+}
+
+func NewPlaceholderRegistry() *PlaceholderRegistry {
+	return &PlaceholderRegistry{
+		placeholders: []RegisteredPlaceholder{},
+	}
+}
+
+func (r *PlaceholderRegistry) Register(module string, active bool, placeholders []PlaceholderInfo) {
+	for _, placeholder := range placeholders {
+		r.placeholders = append(r.placeholders, RegisteredPlaceholder{
+			PlaceholderInfo: placeholder,
+			Module:          module,
+			Active:          active,
+		})
+	}
+}
+
+// Find returns the placeholder matching name, active modules are preferred.
+func (r *PlaceholderRegistry) Find(name string) (RegisteredPlaceholder, bool) {
+	var found *RegisteredPlaceholder
+	for i, placeholder := range r.placeholders {
+		if placeholder.Matches(name) && (found == nil || (!found.Active && placeholder.Active)) {
+			found = &r.placeholders[i]
+		}
+	}
+
+	if found == nil {
+		return RegisteredPlaceholder{}, false
+	}
+	return *found, true
+}
+
+func (r *PlaceholderRegistry) All() []RegisteredPlaceholder {
+	return r.placeholders
+}
+
+// Validate checks that every name is provided by an active module.
+func (r *PlaceholderRegistry) Validate(names []string) error {
+	errs := []error{}
+	for _, name := range names {
+		placeholder, ok := r.Find(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("placeholder {%s} is not provided by any module", name))
+		} else if !placeholder.Active {
+			errs = append(errs, fmt.Errorf("placeholder {%s} is provided by %s which is not active", name, placeholder.Module))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReferencedPlaceholders returns every placeholder used by the pages, scheduled messages and outputs.
+func (c *ChatBoxBuilder) ReferencedPlaceholders() []string {
+	lines := []*ChatBoxLine{}
+	for _, page := range c.pages {
+		lines = append(lines, page.lines...)
+	}
+	for _, output := range c.outputs {
+		lines = append(lines, output.lines...)
+	}
+
+	c.mutex.Lock()
+	for _, scheduled := range c.scheduled {
+		lines = append(lines, scheduled.message.lines...)
+	}
+	c.mutex.Unlock()
+
+	names := []string{}
+	for _, line := range lines {
+		for _, name := range line.template.Placeholders() {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
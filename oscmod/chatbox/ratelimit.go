@@ -0,0 +1,44 @@
+package chatbox
+
+import "time"
+
+const (
+	// VRChat hides the chatbox after a while, unchanged text is resent before that happens.
+	DefaultKeepAlive = 20 * time.Second
+
+	DefaultRateLimitBurst    = 3
+	DefaultRateLimitInterval = 1500 * time.Millisecond
+)
+
+// tokenBucket allows bursts of up to capacity sends and refills one token every interval.
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(capacity int, interval time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		interval: interval,
+	}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
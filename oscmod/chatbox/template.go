@@ -0,0 +1,407 @@
+package chatbox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/locale"
+)
+
+// Template syntax:
+//
+//	{name}                      placeholder, collapses to "" when missing
+//	{name|"fallback"}           fallback text when the placeholder is missing
+//	{name|upper|truncate:20}    filters, applied left to right
+//	{name|marquee:20}           scrolls values longer than 20 characters
+//	{name|bar:10:blocks}        draws a progress bar for a percentage
+//	{if name}...{else}...{end}  conditionals, {if !name} negates
+//	{{ and }}                   literal braces
+
+type TemplateError struct {
+	Template string
+	Position int
+	Message  string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("%q at position %d: %s", e.Template, e.Position, e.Message)
+}
+
+type renderContext struct {
+	lookup     func(name string) (string, bool)
+	locale     *locale.Locale
+	now        time.Time
+	referenced int
+	resolved   int
+}
+
+type templateNode interface {
+	render(ctx *renderContext, out *strings.Builder)
+}
+
+type textNode string
+
+func (n textNode) render(ctx *renderContext, out *strings.Builder) {
+	out.WriteString(string(n))
+}
+
+type filterCall struct {
+	filter *filter
+	args   []string
+}
+
+type placeholderNode struct {
+	name     string
+	filters  []filterCall
+	fallback *string
+}
+
+func (n *placeholderNode) render(ctx *renderContext, out *strings.Builder) {
+	ctx.referenced++
+
+	value, ok := ctx.lookup(n.name)
+	if !ok {
+		if n.fallback == nil {
+			return
+		}
+		value = ctx.locale.T(*n.fallback)
+	}
+	ctx.resolved++
+
+	for _, call := range n.filters {
+		value = call.filter.apply(ctx, value, call.args)
+	}
+	out.WriteString(value)
+}
+
+type conditionalNode struct {
+	name      string
+	negate    bool
+	then      []templateNode
+	otherwise []templateNode
+}
+
+func (n *conditionalNode) render(ctx *renderContext, out *strings.Builder) {
+	value, ok := ctx.lookup(n.name)
+	matches := ok && value != ""
+	if n.negate {
+		matches = !matches
+	}
+
+	nodes := n.otherwise
+	if matches {
+		nodes = n.then
+	}
+
+	for _, node := range nodes {
+		node.render(ctx, out)
+	}
+}
+
+type Template struct {
+	source       string
+	nodes        []templateNode
+	placeholders []string
+}
+
+func ParseTemplate(source string) (*Template, error) {
+	p := &templateParser{source: source}
+
+	nodes, terminator, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	if terminator != "" {
+		return nil, p.errorf(p.tagStart, "unexpected {%s}", terminator)
+	}
+
+	return &Template{
+		source:       source,
+		nodes:        nodes,
+		placeholders: p.placeholders,
+	}, nil
+}
+
+// Placeholders returns every placeholder name referenced by the template, including conditions.
+func (t *Template) Placeholders() []string {
+	return t.placeholders
+}
+
+func (t *Template) Source() string {
+	return t.source
+}
+
+// Render renders the template, ok is false if the template referenced placeholders but none of them
+// resolved. Fallback texts are translated and filters format numbers using locale, animated
+// filters like marquee advance with the wall clock.
+func (t *Template) Render(lookup func(name string) (string, bool), locale *locale.Locale) (string, bool) {
+	ctx := &renderContext{lookup: lookup, locale: locale, now: time.Now()}
+	out := &strings.Builder{}
+	for _, node := range t.nodes {
+		node.render(ctx, out)
+	}
+
+	if ctx.referenced > 0 && ctx.resolved == 0 {
+		return "", false
+	}
+	return out.String(), true
+}
+
+type templateParser struct {
+	source       string
+	position     int
+	tagStart     int
+	placeholders []string
+}
+
+func (p *templateParser) errorf(position int, format string, args ...any) error {
+	return &TemplateError{
+		Template: p.source,
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// parseNodes parses until the end of the source or an {else} / {end} tag, which is returned as terminator.
+func (p *templateParser) parseNodes() ([]templateNode, string, error) {
+	nodes := []templateNode{}
+	text := &strings.Builder{}
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.position < len(p.source) {
+		if strings.HasPrefix(p.source[p.position:], "{{") {
+			text.WriteByte('{')
+			p.position += 2
+			continue
+		}
+
+		if strings.HasPrefix(p.source[p.position:], "}}") {
+			text.WriteByte('}')
+			p.position += 2
+			continue
+		}
+
+		switch p.source[p.position] {
+		case '{':
+			flush()
+
+			p.tagStart = p.position
+			tag, err := p.readTag()
+			if err != nil {
+				return nil, "", err
+			}
+
+			keyword, rest, _ := strings.Cut(tag, " ")
+			switch keyword {
+			case "else", "end":
+				if strings.TrimSpace(rest) != "" {
+					return nil, "", p.errorf(p.tagStart, "unexpected arguments for {%s}", keyword)
+				}
+				return nodes, keyword, nil
+			case "if":
+				node, err := p.parseConditional(strings.TrimSpace(rest))
+				if err != nil {
+					return nil, "", err
+				}
+				nodes = append(nodes, node)
+			default:
+				node, err := p.parsePlaceholder(tag)
+				if err != nil {
+					return nil, "", err
+				}
+				nodes = append(nodes, node)
+			}
+		case '}':
+			return nil, "", p.errorf(p.position, "unmatched }")
+		default:
+			text.WriteByte(p.source[p.position])
+			p.position++
+		}
+	}
+
+	flush()
+	return nodes, "", nil
+}
+
+// readTag reads a {...} tag starting at the current position and returns its trimmed content.
+func (p *templateParser) readTag() (string, error) {
+	start := p.position
+	p.position++
+
+	quoted := false
+	for p.position < len(p.source) {
+		c := p.source[p.position]
+		switch {
+		case quoted && c == '\\':
+			p.position++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == '{':
+			return "", p.errorf(p.position, "unexpected { inside placeholder")
+		case !quoted && c == '}':
+			p.position++
+			tag := strings.TrimSpace(p.source[start+1 : p.position-1])
+			if tag == "" {
+				return "", p.errorf(start, "empty placeholder")
+			}
+			return tag, nil
+		}
+		p.position++
+	}
+
+	return "", p.errorf(start, "unterminated placeholder")
+}
+
+func (p *templateParser) parseConditional(condition string) (templateNode, error) {
+	start := p.tagStart
+
+	node := &conditionalNode{}
+	if strings.HasPrefix(condition, "!") {
+		node.negate = true
+		condition = strings.TrimSpace(condition[1:])
+	}
+
+	if !isValidName(condition) {
+		return nil, p.errorf(start, "invalid condition %q", condition)
+	}
+	node.name = condition
+	p.placeholders = append(p.placeholders, condition)
+
+	then, terminator, err := p.parseNodes()
+	if err != nil {
+		return nil, err
+	}
+	node.then = then
+
+	if terminator == "else" {
+		node.otherwise, terminator, err = p.parseNodes()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if terminator != "end" {
+		return nil, p.errorf(start, "missing {end}")
+	}
+
+	return node, nil
+}
+
+func (p *templateParser) parsePlaceholder(tag string) (templateNode, error) {
+	parts, err := splitQuoted(tag, '|')
+	if err != nil {
+		return nil, p.errorf(p.tagStart, "%v", err)
+	}
+
+	name := strings.TrimSpace(parts[0])
+	if !isValidName(name) {
+		return nil, p.errorf(p.tagStart, "invalid placeholder name %q", name)
+	}
+
+	node := &placeholderNode{name: name}
+	p.placeholders = append(p.placeholders, name)
+
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+
+		if strings.HasPrefix(part, "\"") {
+			if node.fallback != nil {
+				return nil, p.errorf(p.tagStart, "more than one fallback for %s", name)
+			}
+			fallback, err := unquote(part)
+			if err != nil {
+				return nil, p.errorf(p.tagStart, "%v", err)
+			}
+			node.fallback = &fallback
+			continue
+		}
+
+		fields, err := splitQuoted(part, ':')
+		if err != nil {
+			return nil, p.errorf(p.tagStart, "%v", err)
+		}
+
+		filter, ok := filters[strings.TrimSpace(fields[0])]
+		if !ok {
+			return nil, p.errorf(p.tagStart, "unknown filter %q", fields[0])
+		}
+
+		args := []string{}
+		for _, field := range fields[1:] {
+			arg, err := unquote(strings.TrimSpace(field))
+			if err != nil {
+				return nil, p.errorf(p.tagStart, "%v", err)
+			}
+			args = append(args, arg)
+		}
+
+		err = filter.check(args)
+		if err != nil {
+			return nil, p.errorf(p.tagStart, "filter %s: %v", fields[0], err)
+		}
+
+		node.filters = append(node.filters, filterCall{filter: filter, args: args})
+	}
+
+	return node, nil
+}
+
+func isValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	return !strings.ContainsAny(name, " \t|\"{}:!")
+}
+
+// splitQuoted splits s at sep, ignoring separators inside double quotes.
+func splitQuoted(s string, sep byte) ([]string, error) {
+	parts := []string{}
+	quoted := false
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated string")
+	}
+
+	return append(parts, s[start:]), nil
+}
+
+// unquote removes surrounding double quotes and resolves \" and \\, bare words are returned as is.
+func unquote(s string) (string, error) {
+	if !strings.HasPrefix(s, "\"") {
+		return s, nil
+	}
+
+	if len(s) < 2 || !strings.HasSuffix(s, "\"") {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+
+	out := &strings.Builder{}
+	inner := s[1 : len(s)-1]
+	for i := 0; i < len(inner); i++ {
+		if inner[i] == '\\' && i+1 < len(inner) {
+			i++
+		}
+		out.WriteByte(inner[i])
+	}
+	return out.String(), nil
+}
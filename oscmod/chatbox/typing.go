@@ -0,0 +1,73 @@
+package chatbox
+
+import (
+	"log"
+	"time"
+
+	"github.com/hypebeast/go-osc/osc"
+)
+
+// BeginTyping shows the typing indicator until EndTyping is called with the same reason, e.g.
+// while a module runs a slow operation.
+func (c *ChatBoxBuilder) BeginTyping(reason string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.typing[reason] = true
+}
+
+func (c *ChatBoxBuilder) EndTyping(reason string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.typing, reason)
+}
+
+// Clear clears the chatbox on the next flush, it stays empty until its content changes.
+func (c *ChatBoxBuilder) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clearRequested = true
+}
+
+// SetTransitionTyping shows the typing indicator for delay before a new page is shown.
+func (c *ChatBoxBuilder) SetTransitionTyping(delay time.Duration) {
+	c.transitionDelay = delay
+}
+
+// Shutdown clears the chatbox, typing indicator and outputs, nothing is sent after it.
+func (c *ChatBoxBuilder) Shutdown(client *osc.Client) {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+
+	c.closed = true
+	c.closeOutputs()
+
+	c.sendTyping(client, false)
+	err := c.sendText(client, "", c.defaultFlags, time.Now())
+	if err != nil {
+		log.Printf("Failed to clear chatbox: %v", err)
+	}
+}
+
+// updateTyping sends /chatbox/typing whenever the requested state changes.
+func (c *ChatBoxBuilder) updateTyping(client *osc.Client) {
+	c.mutex.Lock()
+	typing := len(c.typing) > 0
+	c.mutex.Unlock()
+
+	if typing != c.typingSent {
+		c.sendTyping(client, typing)
+	}
+}
+
+func (c *ChatBoxBuilder) sendTyping(client *osc.Client, typing bool) {
+	msg := osc.NewMessage("/chatbox/typing")
+	msg.Append(typing)
+
+	err := client.Send(msg)
+	if err != nil {
+		log.Printf("Failed to send message: %v", err)
+		return
+	}
+
+	c.typingSent = typing
+}
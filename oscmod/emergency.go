@@ -0,0 +1,23 @@
+package oscmod
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+var emergencyStop atomic.Bool
+
+// EmergencyStop stops (or releases) all movement and OpenShock actions across every module.
+func EmergencyStop(stop bool) {
+	if emergencyStop.Swap(stop) != stop {
+		if stop {
+			log.Println("Emergency stop engaged")
+		} else {
+			log.Println("Emergency stop released")
+		}
+	}
+}
+
+func IsEmergencyStopped() bool {
+	return emergencyStop.Load()
+}
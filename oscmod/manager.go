@@ -0,0 +1,191 @@
+package oscmod
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/hypebeast/go-osc/osc"
+)
+
+// Pausable is implemented by modules that keep doing work outside of Tick (OSC handlers, tickers)
+// and need to know when they get disabled.
+type Pausable interface {
+	SetPaused(paused bool)
+}
+
+// StatusReporter is implemented by modules that can describe their current state.
+type StatusReporter interface {
+	Status() []string
+}
+
+// Stopper is implemented by modules with background work that has to stop on shutdown.
+type Stopper interface {
+	Shutdown()
+}
+
+type ModuleStatus struct {
+	Id        string
+	Name      string
+	Enabled   bool
+	LastError error
+	LastTick  time.Time
+	Status    []string
+}
+
+type managedModule struct {
+	module    OSCModule
+	enabled   bool
+	lastError error
+	lastTick  time.Time
+}
+
+type ModuleManager struct {
+	modules []*managedModule
+	mutex   sync.Mutex
+
+	// defaultHandlers receive every incoming message, see AddDefaultHandler.
+	defaultHandlers []osc.HandlerFunc
+}
+
+func NewModuleManager() *ModuleManager {
+	return &ModuleManager{
+		modules:         []*managedModule{},
+		defaultHandlers: []osc.HandlerFunc{},
+	}
+}
+
+// RegisterDefaultHandler makes the manager the default handler of dispatcher. go-osc only has a
+// single default handler, so nothing else may register "*" and uses AddDefaultHandler instead.
+func (m *ModuleManager) RegisterDefaultHandler(dispatcher *osc.StandardDispatcher) error {
+	return dispatcher.AddMsgHandler("*", m.handleDefault)
+}
+
+// AddDefaultHandler adds a handler that is called for every incoming message.
+func (m *ModuleManager) AddDefaultHandler(handler osc.HandlerFunc) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.defaultHandlers = append(m.defaultHandlers, handler)
+}
+
+func (m *ModuleManager) handleDefault(msg *osc.Message) {
+	m.mutex.Lock()
+	handlers := append([]osc.HandlerFunc{}, m.defaultHandlers...)
+	m.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(msg)
+	}
+}
+
+func (m *ModuleManager) Add(module OSCModule) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.modules = append(m.modules, &managedModule{
+		module:  module,
+		enabled: true,
+	})
+}
+
+func (m *ModuleManager) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) {
+	m.mutex.Lock()
+	modules := append([]*managedModule{}, m.modules...)
+	m.mutex.Unlock()
+
+	for _, managed := range modules {
+		m.mutex.Lock()
+		enabled := managed.enabled
+		m.mutex.Unlock()
+
+		if !enabled {
+			continue
+		}
+
+		err := managed.module.Tick(client, chatbox)
+		if err != nil {
+			log.Printf("Failed to tick module: %s (%v)", managed.module.Name(), err)
+		}
+
+		m.mutex.Lock()
+		managed.lastError = err
+		managed.lastTick = time.Now()
+		m.mutex.Unlock()
+	}
+}
+
+func (m *ModuleManager) SetEnabled(id string, enabled bool) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, managed := range m.modules {
+		if managed.module.Id() == id {
+			managed.enabled = enabled
+			if pausable, ok := managed.module.(Pausable); ok {
+				pausable.SetPaused(!enabled)
+			}
+
+			if enabled {
+				log.Printf("Enabled %s", managed.module.Name())
+			} else {
+				log.Printf("Disabled %s", managed.module.Name())
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("module %s is not active", id)
+}
+
+func (m *ModuleManager) Toggle(id string) error {
+	m.mutex.Lock()
+	enabled := false
+	for _, managed := range m.modules {
+		if managed.module.Id() == id {
+			enabled = managed.enabled
+		}
+	}
+	m.mutex.Unlock()
+
+	return m.SetEnabled(id, !enabled)
+}
+
+func (m *ModuleManager) Statuses() []ModuleStatus {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	statuses := []ModuleStatus{}
+	for _, managed := range m.modules {
+		status := ModuleStatus{
+			Id:        managed.module.Id(),
+			Name:      managed.module.Name(),
+			Enabled:   managed.enabled,
+			LastError: managed.lastError,
+			LastTick:  managed.lastTick,
+		}
+
+		if reporter, ok := managed.module.(StatusReporter); ok {
+			status.Status = reporter.Status()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// Shutdown stops the background work of all modules.
+func (m *ModuleManager) Shutdown() {
+	m.mutex.Lock()
+	modules := append([]*managedModule{}, m.modules...)
+	m.mutex.Unlock()
+
+	for _, managed := range modules {
+		if stopper, ok := managed.module.(Stopper); ok {
+			stopper.Shutdown()
+		}
+	}
+}
@@ -8,6 +8,14 @@ import (
 type OSCModule interface {
 	Name() string
 	Id() string
+	// ConfigSection returns the key of the config section used by the module or "" if it has none.
+	ConfigSection() string
+	// Addresses returns the OSC addresses the module handles.
+	Addresses() []string
+	// Placeholders returns the chatbox placeholders the module provides.
+	Placeholders() []chatbox.PlaceholderInfo
+	// Init registers the OSC handlers of the module. The default handler "*" belongs to the
+	// ModuleManager and must not be registered by modules.
 	Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error
 	Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error
 }
@@ -3,35 +3,39 @@ package modules
 import (
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/Glowman554/OpenOSC/config"
 	"github.com/Glowman554/OpenOSC/gpuinfo"
+	"github.com/Glowman554/OpenOSC/locale"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/hypebeast/go-osc/osc"
 )
 
-type GpuInfoModuleContainer struct {
-	usageAMD    []gpuinfo.GPUUsage
-	usageNVIDIA []gpuinfo.GPUUsage
+// gpuInfoTTL keeps the last reading visible while the vendor tools are slow or fail.
+const gpuInfoTTL = 10 * time.Second
 
+type GpuInfoModuleContainer struct {
 	providerAMD    *gpuinfo.AMDProvider
 	providerNVIDIA *gpuinfo.NvidiaProvider
+	providerSysfs  *gpuinfo.SysfsProvider
 
 	config config.GpuInfoConfig
+	locale *locale.Locale
 }
 
 type GpuInfoModule struct {
 	container *GpuInfoModuleContainer
 }
 
-func NewGpuInfoModule(config config.GpuInfoConfig) GpuInfoModule {
+func NewGpuInfoModule(config config.GpuInfoConfig, locale *locale.Locale) GpuInfoModule {
 	return GpuInfoModule{
 		container: &GpuInfoModuleContainer{
-			usageAMD:       []gpuinfo.GPUUsage{},
-			usageNVIDIA:    []gpuinfo.GPUUsage{},
 			providerAMD:    nil,
 			providerNVIDIA: nil,
+			providerSysfs:  nil,
 			config:         config,
+			locale:         locale,
 		},
 	}
 }
@@ -44,6 +48,40 @@ func (m GpuInfoModule) Id() string {
 	return "gpuinfo"
 }
 
+func (m GpuInfoModule) ConfigSection() string {
+	return "gpuInfo"
+}
+
+func (m GpuInfoModule) Addresses() []string {
+	return []string{}
+}
+
+func (m GpuInfoModule) Placeholders() []chatbox.PlaceholderInfo {
+	placeholders := gpuPlaceholders("amd", "Radeon RX 7900 XTX", "AMD")
+	placeholders = append(placeholders, gpuPlaceholders("nvidia", "NVIDIA GeForce RTX 4080", "NVIDIA")...)
+	return append(placeholders, gpuPlaceholders("drm", "Iris Xe Graphics", "Intel")...)
+}
+
+func gpuPlaceholders(prefix string, exampleName string, exampleVendor string) []chatbox.PlaceholderInfo {
+	prefix = "gpuinfo." + prefix + "*"
+	return []chatbox.PlaceholderInfo{
+		{Name: prefix + ".name", Description: "Name of the GPU, * is the GPU index", Example: exampleName, TTL: gpuInfoTTL},
+		{Name: prefix + ".vendor", Description: "Vendor of the GPU", Example: exampleVendor, TTL: gpuInfoTTL},
+		{Name: prefix + ".usage", Description: "GPU utilization, if the driver reports it", Example: "34%", TTL: gpuInfoTTL},
+		{Name: prefix + ".memory", Description: "VRAM usage, not set for integrated GPUs", Example: "21%", TTL: gpuInfoTTL},
+		{Name: prefix + ".memory.total", Description: "Total VRAM in MB", Example: "16376", TTL: gpuInfoTTL},
+		{Name: prefix + ".memory.used", Description: "Used VRAM in MB", Example: "3480", TTL: gpuInfoTTL},
+		{Name: prefix + ".temperature", Description: "GPU temperature", Example: "61°C", TTL: gpuInfoTTL},
+		{Name: prefix + ".power", Description: "Power draw", Example: "187 W", TTL: gpuInfoTTL},
+		{Name: prefix + ".power.limit", Description: "Power limit", Example: "303 W", TTL: gpuInfoTTL},
+		{Name: prefix + ".clock.core", Description: "Core clock", Example: "2482 MHz", TTL: gpuInfoTTL},
+		{Name: prefix + ".clock.memory", Description: "Memory clock", Example: "1249 MHz", TTL: gpuInfoTTL},
+		{Name: prefix + ".fan", Description: "Fan speed", Example: "32%", TTL: gpuInfoTTL},
+		{Name: prefix + ".encoder", Description: "Video encoder utilization, NVIDIA only", Example: "12%", TTL: gpuInfoTTL},
+		{Name: prefix + ".decoder", Description: "Video decoder utilization, NVIDIA only", Example: "0%", TTL: gpuInfoTTL},
+	}
+}
+
 func (m GpuInfoModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
 	if gpuinfo.CanUseAMDProvider() && m.container.config.EnableAmd {
 		m.container.providerAMD = gpuinfo.NewAMDProvider()
@@ -55,51 +93,105 @@ func (m GpuInfoModule) Init(client *osc.Client, dispatcher *osc.StandardDispatch
 		log.Print("Enabled NVIDIA provider")
 	}
 
-	return nil
-}
-
-func (m GpuInfoModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
-	m.triggerMeasure()
-
-	for _, info := range m.container.usageAMD {
-		m.register(chatbox, "amd", info)
+	// cards the vendor tools report are left out so they are not listed twice
+	skipVendors := []string{}
+	if m.container.providerAMD != nil {
+		skipVendors = append(skipVendors, gpuinfo.VendorIDAMD)
+	}
+	if m.container.providerNVIDIA != nil {
+		skipVendors = append(skipVendors, gpuinfo.VendorIDNVIDIA)
 	}
 
-	for _, info := range m.container.usageNVIDIA {
-		m.register(chatbox, "nvidia", info)
+	if gpuinfo.CanUseSysfsProvider(m.container.config.SysfsRoot, skipVendors...) && m.container.config.EnableSysfs {
+		m.container.providerSysfs = gpuinfo.NewSysfsProvider(m.container.config.SysfsRoot, skipVendors...)
+		log.Print("Enabled sysfs provider")
 	}
 
 	return nil
 }
 
+func (m GpuInfoModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
+	m.triggerMeasure(chatbox)
+	return nil
+}
+
 func (m GpuInfoModule) register(chatbox *chatbox.ChatBoxBuilder, prefix string, info gpuinfo.GPUUsage) {
-	chatbox.Placeholder("gpuinfo."+prefix+strconv.Itoa(info.Index)+".name", info.Name)
-	chatbox.Placeholder("gpuinfo."+prefix+strconv.Itoa(info.Index)+".vendor", info.Vendor)
-	chatbox.Placeholder("gpuinfo."+prefix+strconv.Itoa(info.Index)+".usage", strconv.Itoa(info.Utilization)+"%")
-	chatbox.Placeholder(
-		"gpuinfo."+prefix+strconv.Itoa(info.Index)+".memory",
-		strconv.Itoa(int(float64(info.MemoryUsedMB)/float64(info.MemoryTotalMB)*100))+"%",
-	)
-	chatbox.Placeholder("gpuinfo."+prefix+strconv.Itoa(info.Index)+".memory.total", strconv.Itoa(info.MemoryTotalMB))
-	chatbox.Placeholder("gpuinfo."+prefix+strconv.Itoa(info.Index)+".memory.used", strconv.Itoa(info.MemoryUsedMB))
+	prefix = "gpuinfo." + prefix + strconv.Itoa(info.Index)
+
+	chatbox.StickyPlaceholder(prefix+".name", info.Name, gpuInfoTTL)
+	chatbox.StickyPlaceholder(prefix+".vendor", info.Vendor, gpuInfoTTL)
+	if info.Utilization >= 0 {
+		chatbox.StickyPlaceholder(prefix+".usage", m.container.locale.Percent(info.Utilization), gpuInfoTTL)
+	}
+	if info.MemoryTotalMB > 0 {
+		chatbox.StickyPlaceholder(
+			prefix+".memory",
+			m.container.locale.Percent(int(float64(info.MemoryUsedMB)/float64(info.MemoryTotalMB)*100)),
+			gpuInfoTTL,
+		)
+		chatbox.StickyPlaceholder(prefix+".memory.total", strconv.Itoa(info.MemoryTotalMB), gpuInfoTTL)
+		chatbox.StickyPlaceholder(prefix+".memory.used", strconv.Itoa(info.MemoryUsedMB), gpuInfoTTL)
+	}
+	if info.TemperatureC > 0 {
+		chatbox.StickyPlaceholder(prefix+".temperature", m.container.locale.FormatNumber(info.TemperatureC, 0)+"°C", gpuInfoTTL)
+	}
+	if info.PowerW > 0 {
+		chatbox.StickyPlaceholder(prefix+".power", m.container.locale.FormatNumber(info.PowerW, 0)+" W", gpuInfoTTL)
+	}
+	if info.PowerLimitW > 0 {
+		chatbox.StickyPlaceholder(prefix+".power.limit", m.container.locale.FormatNumber(info.PowerLimitW, 0)+" W", gpuInfoTTL)
+	}
+	if info.CoreClockMHz > 0 {
+		chatbox.StickyPlaceholder(prefix+".clock.core", strconv.Itoa(info.CoreClockMHz)+" MHz", gpuInfoTTL)
+	}
+	if info.MemoryClockMHz > 0 {
+		chatbox.StickyPlaceholder(prefix+".clock.memory", strconv.Itoa(info.MemoryClockMHz)+" MHz", gpuInfoTTL)
+	}
+	if info.FanPercent >= 0 {
+		chatbox.StickyPlaceholder(prefix+".fan", m.container.locale.Percent(info.FanPercent), gpuInfoTTL)
+	}
+	if info.EncoderUtilization >= 0 {
+		chatbox.StickyPlaceholder(prefix+".encoder", m.container.locale.Percent(info.EncoderUtilization), gpuInfoTTL)
+	}
+	if info.DecoderUtilization >= 0 {
+		chatbox.StickyPlaceholder(prefix+".decoder", m.container.locale.Percent(info.DecoderUtilization), gpuInfoTTL)
+	}
 }
 
-func (m GpuInfoModule) triggerMeasure() {
+// triggerMeasure reads the GPUs in the background, on failure the previous values stay until they expire.
+func (m GpuInfoModule) triggerMeasure(chatbox *chatbox.ChatBoxBuilder) {
 	go func() {
 		if m.container.providerAMD != nil {
 			amd, err := m.container.providerAMD.Read()
 			if err != nil {
 				log.Printf("%v", err)
+			} else {
+				for _, info := range amd {
+					m.register(chatbox, "amd", info)
+				}
 			}
-			m.container.usageAMD = amd
 		}
 
 		if m.container.providerNVIDIA != nil {
 			nvidia, err := m.container.providerNVIDIA.Read()
 			if err != nil {
 				log.Printf("%v", err)
+			} else {
+				for _, info := range nvidia {
+					m.register(chatbox, "nvidia", info)
+				}
+			}
+		}
+
+		if m.container.providerSysfs != nil {
+			drm, err := m.container.providerSysfs.Read()
+			if err != nil {
+				log.Printf("%v", err)
+			} else {
+				for _, info := range drm {
+					m.register(chatbox, "drm", info)
+				}
 			}
-			m.container.usageNVIDIA = nvidia
 		}
 	}()
 }
@@ -1,7 +1,10 @@
 package modules
 
 import (
+	"fmt"
 	"math"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Glowman554/OpenOSC/config"
 	"github.com/Glowman554/OpenOSC/oscmod"
@@ -14,6 +17,9 @@ type LeashModuleContainer struct {
 	isRunning bool
 	isGrabbed bool
 
+	paused atomic.Bool
+	halted bool
+
 	stretch float64
 
 	smoothMoveX float64
@@ -26,6 +32,10 @@ type LeashModuleContainer struct {
 	yNeg float64
 	zNeg float64
 
+	// mutex guards the state above, it is written by the OSC handlers and read by the movement
+	// ticker and the status.
+	mutex sync.Mutex
+
 	config config.LeashConfig
 }
 
@@ -61,10 +71,33 @@ func (m LeashModule) Id() string {
 	return "leash"
 }
 
+func (m LeashModule) ConfigSection() string {
+	return "leashConfig"
+}
+
+func (m LeashModule) Addresses() []string {
+	return []string{
+		"/avatar/parameters/Leash_IsGrabbed",
+		"/avatar/parameters/Leash_Stretch",
+		"/avatar/parameters/Leash_Z+",
+		"/avatar/parameters/Leash_Z-",
+		"/avatar/parameters/Leash_X+",
+		"/avatar/parameters/Leash_X-",
+		"/avatar/parameters/Leash_Y+",
+		"/avatar/parameters/Leash_Y-",
+	}
+}
+
+func (m LeashModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{}
+}
+
 func (m LeashModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
 	err := dispatcher.AddMsgHandler("/avatar/parameters/Leash_IsGrabbed", func(msg *osc.Message) {
 		if grabbed, ok := msg.Arguments[0].(bool); ok {
+			m.container.mutex.Lock()
 			m.container.isGrabbed = grabbed
+			m.container.mutex.Unlock()
 			// if grabbed {
 			// 	log.Println("Leash grabbed")
 			// } else {
@@ -78,34 +111,39 @@ func (m LeashModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/Leash_Stretch", func(msg *osc.Message) {
 		if stretch, ok := msg.Arguments[0].(float32); ok {
+			m.container.mutex.Lock()
 			m.container.stretch = float64(stretch)
+			m.container.mutex.Unlock()
 		}
 	})
 	if err != nil {
 		return err
 	}
 
-	// TODO: should not be *
-	err = dispatcher.AddMsgHandler("*", func(msg *osc.Message) {
-		if val, ok := msg.Arguments[0].(float32); ok {
-			switch msg.Address {
-			case "/avatar/parameters/Leash_Z+":
-				m.container.zPos = float64(val)
-			case "/avatar/parameters/Leash_Z-":
-				m.container.zNeg = float64(val)
-			case "/avatar/parameters/Leash_X+":
-				m.container.xPos = float64(val)
-			case "/avatar/parameters/Leash_X-":
-				m.container.xNeg = float64(val)
-			case "/avatar/parameters/Leash_Y+":
-				m.container.yPos = float64(val)
-			case "/avatar/parameters/Leash_Y-":
-				m.container.yNeg = float64(val)
+	directions := map[string]*float64{
+		"/avatar/parameters/Leash_Z+": &m.container.zPos,
+		"/avatar/parameters/Leash_Z-": &m.container.zNeg,
+		"/avatar/parameters/Leash_X+": &m.container.xPos,
+		"/avatar/parameters/Leash_X-": &m.container.xNeg,
+		"/avatar/parameters/Leash_Y+": &m.container.yPos,
+		"/avatar/parameters/Leash_Y-": &m.container.yNeg,
+	}
+	for address, direction := range directions {
+		err = dispatcher.AddMsgHandler(address, func(msg *osc.Message) {
+			// go-osc matches addresses as regular expressions without escaping the +, so
+			// Leash_X+ also reaches the Leash_X- handler
+			if msg.Address != address {
+				return
+			}
+			if val, ok := msg.Arguments[0].(float32); ok {
+				m.container.mutex.Lock()
+				*direction = float64(val)
+				m.container.mutex.Unlock()
 			}
+		})
+		if err != nil {
+			return err
 		}
-	})
-	if err != nil {
-		return err
 	}
 
 	player := oscmod.NewPlayer(client)
@@ -120,7 +158,51 @@ func (m LeashModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) e
 	return nil
 }
 
+func (m LeashModule) SetPaused(paused bool) {
+	m.container.paused.Store(paused)
+}
+
+func (m LeashModule) Status() []string {
+	c := m.container
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := "idle"
+	if c.isRunning {
+		state = "running"
+	} else if c.isWalking {
+		state = "walking"
+	}
+	if c.halted {
+		state = "halted"
+	}
+
+	return []string{
+		fmt.Sprintf("grabbed: %t  stretch: %.3f  %s", c.isGrabbed, c.stretch, state),
+		fmt.Sprintf("X+ %.3f  X- %.3f", c.xPos, c.xNeg),
+		fmt.Sprintf("Y+ %.3f  Y- %.3f", c.yPos, c.yNeg),
+		fmt.Sprintf("Z+ %.3f  Z- %.3f", c.zPos, c.zNeg),
+		fmt.Sprintf("move x: %.3f  z: %.3f", c.smoothMoveX, c.smoothMoveZ),
+	}
+}
+
 func (c *LeashModuleContainer) UpdateMovement(player *oscmod.Player) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.paused.Load() || oscmod.IsEmergencyStopped() {
+		if !c.halted {
+			c.halted = true
+			c.smoothMoveX = 0
+			c.smoothMoveZ = 0
+			player.StopRun()
+			player.MoveVertical(0)
+			player.MoveHorizontal(0)
+		}
+		return
+	}
+	c.halted = false
+
 	c.UpdateMovementState()
 	x, y, z := c.CalculateMovement()
 	c.ApplyMovement(player, x, y, z)
@@ -0,0 +1,231 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/mpris"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/hypebeast/go-osc/osc"
+)
+
+// mediaArtTrack is the JSON served on /current for overlay pages.
+type mediaArtTrack struct {
+	Player   string   `json:"player"`
+	Playing  bool     `json:"playing"`
+	Title    string   `json:"title"`
+	Artist   []string `json:"artist"`
+	Album    string   `json:"album"`
+	URL      string   `json:"url"`
+	Art      string   `json:"art"`
+	Position float64  `json:"position"`
+	Duration float64  `json:"duration"`
+}
+
+type MediaArtModuleContainer struct {
+	selector *mpris.Selector
+	config   config.MediaArtConfig
+	// baseURL is the address of the server put into the art URLs.
+	baseURL string
+}
+
+type MediaArtModule struct {
+	container *MediaArtModuleContainer
+}
+
+func NewMediaArtModule(selector *mpris.Selector, config config.MediaArtConfig) MediaArtModule {
+	return MediaArtModule{
+		container: &MediaArtModuleContainer{
+			selector: selector,
+			config:   config,
+		},
+	}
+}
+
+func (m MediaArtModule) Name() string {
+	return "Media art"
+}
+
+func (m MediaArtModule) Id() string {
+	return "media_art"
+}
+
+func (m MediaArtModule) ConfigSection() string {
+	return "mediaArt"
+}
+
+func (m MediaArtModule) Addresses() []string {
+	return []string{}
+}
+
+func (m MediaArtModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{
+		{Name: "media.art", Description: "Local URL of the cover art of the selected track, e.g. for OBS browser sources", Example: "http://127.0.0.1:9557/art?v=1a2b3c4d"},
+	}
+}
+
+func (m MediaArtModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
+	err := m.container.selector.Watcher().Start()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", m.container.config.Address)
+	if err != nil {
+		log.Printf("Failed to start cover art server: %v", err)
+		return err
+	}
+	m.container.baseURL = mediaArtBaseURL(listener.Addr().(*net.TCPAddr), m.container.config.PublicHost)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/art", m.handleArt)
+	mux.HandleFunc("/current", m.handleCurrent)
+
+	go func() {
+		err := http.Serve(listener, mux)
+		if err != nil {
+			log.Printf("Cover art server stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func (m MediaArtModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
+	_, playing, ok := m.container.selector.Current()
+	if ok && playing.ArtURL != "" {
+		chatbox.Placeholder("media.art", m.artURL(playing.ArtURL))
+	}
+
+	return nil
+}
+
+// artURL points to the art endpoint, the version changes with the art so overlays reload it.
+func (m MediaArtModule) artURL(art string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(art))
+	return fmt.Sprintf("%s/art?v=%08x", m.container.baseURL, hash.Sum32())
+}
+
+// mediaArtBaseURL is where overlays reach the server listening on addr, a server listening on
+// all interfaces is advertised on localhost unless publicHost is set.
+func mediaArtBaseURL(addr *net.TCPAddr, publicHost string) string {
+	host := publicHost
+	if host == "" {
+		host = addr.IP.String()
+		if addr.IP.IsUnspecified() {
+			host = "127.0.0.1"
+		}
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(addr.Port))
+}
+
+// handleArt serves the cover art of the selected track, local files are served directly and
+// remote art is redirected to.
+func (m MediaArtModule) handleArt(w http.ResponseWriter, r *http.Request) {
+	_, playing, ok := m.container.selector.Current()
+	if !ok || playing.ArtURL == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	art, err := url.Parse(playing.ArtURL)
+	if err != nil {
+		http.Error(w, "invalid art url", http.StatusBadGateway)
+		return
+	}
+
+	switch art.Scheme {
+	case "file":
+		m.serveFile(w, r, art.Path)
+	case "http", "https":
+		http.Redirect(w, r, playing.ArtURL, http.StatusFound)
+	default:
+		http.Error(w, "unsupported art url scheme "+art.Scheme, http.StatusNotImplemented)
+	}
+}
+
+// serveFile serves a local cover art file. The path comes from whatever player is on the session
+// bus, so only regular files that look like images are served, never e.g. keys or configs.
+func (m MediaArtModule) serveFile(w http.ResponseWriter, r *http.Request, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil || !stat.Mode().IsRegular() {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		http.NotFound(w, r)
+		return
+	}
+	contentType := http.DetectContentType(header[:n])
+	if !strings.HasPrefix(contentType, "image/") {
+		log.Printf("Refusing to serve %s as cover art (%s)", path, contentType)
+		http.Error(w, "cover art is not an image", http.StatusForbidden)
+		return
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		http.Error(w, "failed to read cover art", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-store")
+	http.ServeContent(w, r, filepath.Base(path), stat.ModTime(), file)
+}
+
+func (m MediaArtModule) handleCurrent(w http.ResponseWriter, r *http.Request) {
+	player, playing, ok := m.container.selector.Current()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	track := mediaArtTrack{
+		Player:   m.container.selector.Identity(player),
+		Playing:  playing.Status == mpris.Playing,
+		Title:    playing.Title,
+		Artist:   playing.Artist,
+		Album:    playing.Album,
+		URL:      playing.URL,
+		Position: playing.Position.Seconds(),
+		Duration: playing.Duration.Seconds(),
+	}
+	if playing.ArtURL != "" {
+		track.Art = m.artURL(playing.ArtURL)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	// the track can contain local paths, so only the configured origin may read it from a page
+	if m.container.config.AllowedOrigin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", m.container.config.AllowedOrigin)
+		w.Header().Set("Vary", "Origin")
+	}
+	err := json.NewEncoder(w).Encode(track)
+	if err != nil {
+		log.Printf("Failed to write current track: %v", err)
+	}
+}
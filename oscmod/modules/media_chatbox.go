@@ -2,26 +2,55 @@ package modules
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/locale"
 	"github.com/Glowman554/OpenOSC/mpris"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/hypebeast/go-osc/osc"
 )
 
+// mediaTTL keeps the track visible if a slow or failing player misses a tick, the placeholders are
+// removed right away once nothing is playing.
+const mediaTTL = 6 * time.Second
+
+var mediaTrackPlaceholders = []string{
+	"media.title",
+	"media.album",
+	"media.artist",
+	"media.albumartist",
+	"media.tracknumber",
+	"media.genre",
+	"media.rating",
+	"media.progress",
+	"media.position",
+	"media.duration",
+	"media.percent",
+	"media.player",
+	"media.player.id",
+	"media.url",
+}
+
 type MediaChatBoxModuleContainer struct {
-	dbus *mpris.DBUSInterface
+	selector *mpris.Selector
+	config   config.MediaConfig
+	locale   *locale.Locale
 }
 
 type MediaChatBoxModule struct {
 	container *MediaChatBoxModuleContainer
 }
 
-func NewMediaChatBoxModule() MediaChatBoxModule {
+func NewMediaChatBoxModule(selector *mpris.Selector, config config.MediaConfig, locale *locale.Locale) MediaChatBoxModule {
 	return MediaChatBoxModule{
 		container: &MediaChatBoxModuleContainer{
-			dbus: &mpris.DBUSInterface{},
+			selector: selector,
+			config:   config,
+			locale:   locale,
 		},
 	}
 }
@@ -34,34 +63,79 @@ func (m MediaChatBoxModule) Id() string {
 	return "media_chatbox"
 }
 
+func (m MediaChatBoxModule) ConfigSection() string {
+	return "media"
+}
+
+func (m MediaChatBoxModule) Addresses() []string {
+	return []string{}
+}
+
+func (m MediaChatBoxModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{
+		{Name: "media.title", Description: "Title of the playing track", Example: "Never Gonna Give You Up", TTL: mediaTTL},
+		{Name: "media.artist", Description: "Artists of the playing track", Example: "Rick Astley", TTL: mediaTTL},
+		{Name: "media.album", Description: "Album of the playing track", Example: "Whenever You Need Somebody", TTL: mediaTTL},
+		{Name: "media.albumartist", Description: "Album artists of the playing track", Example: "Rick Astley", TTL: mediaTTL},
+		{Name: "media.tracknumber", Description: "Track number on the album", Example: "1", TTL: mediaTTL},
+		{Name: "media.genre", Description: "Genres of the playing track", Example: "Pop", TTL: mediaTTL},
+		{Name: "media.rating", Description: "User rating as stars, if the player supports ratings", Example: "★★★★☆", TTL: mediaTTL},
+		{Name: "media.progress", Description: "Progress bar with position and duration", Example: "|xxxxxxx--------| 01:42 / 03:33", TTL: mediaTTL},
+		{Name: "media.position", Description: "Playback position", Example: "01:42", TTL: mediaTTL},
+		{Name: "media.duration", Description: "Track length", Example: "03:33", TTL: mediaTTL},
+		{Name: "media.percent", Description: "Playback progress in percent, e.g. for the bar filter", Example: "47", TTL: mediaTTL},
+		{Name: "media.player", Description: "Name of the playing player", Example: "Spotify", TTL: mediaTTL},
+		{Name: "media.player.id", Description: "D-Bus name of the playing player", Example: "org.mpris.MediaPlayer2.spotify", TTL: mediaTTL},
+		{Name: "media.url", Description: "URL of the playing track, if the player exposes one", Example: "https://open.spotify.com/track/4PTG3Z6ehGkBFwjybzWkR8", TTL: mediaTTL},
+		{Name: "media.status", Description: "Translated playback status (Playing, Paused or nothing playing)", Example: "Playing", TTL: mediaTTL},
+	}
+}
+
 func (m MediaChatBoxModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
-	err := m.container.dbus.Connect()
-	return err
+	return m.container.selector.Watcher().Start()
 }
 
 func (m MediaChatBoxModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
-	players, err := m.container.dbus.LoadPlayers()
-	if err != nil {
-		return err
+	status := "nothing playing"
+	player, playing, ok := m.container.selector.Current()
+	if ok && playing.Status == mpris.Paused {
+		status = "Paused"
 	}
 
-	for _, player := range players {
-		playing, err := m.container.dbus.LoadCurrentlyPlaying(player)
-		if err != nil {
-			return err
+	if ok && playing.Status == mpris.Playing {
+		status = "Playing"
+
+		chatbox.StickyPlaceholder("media.title", playing.Title, mediaTTL)
+		chatbox.StickyPlaceholder("media.album", playing.Album, mediaTTL)
+		chatbox.StickyPlaceholder("media.artist", strings.Join(playing.Artist, ", "), mediaTTL)
+		chatbox.StickyPlaceholder("media.albumartist", strings.Join(playing.AlbumArtist, ", "), mediaTTL)
+		chatbox.StickyPlaceholder("media.genre", strings.Join(playing.Genre, ", "), mediaTTL)
+		if playing.TrackNumber > 0 {
+			chatbox.StickyPlaceholder("media.tracknumber", strconv.Itoa(playing.TrackNumber), mediaTTL)
+		} else {
+			chatbox.RemovePlaceholder("media.tracknumber")
 		}
-
-		if playing.Status == mpris.Playing {
-			chatbox.Placeholder("media.title", playing.Title)
-			chatbox.Placeholder("media.album", playing.Album)
-			chatbox.Placeholder("media.artist", strings.Join(playing.Artist, ", "))
-			chatbox.Placeholder("media.progress", m.makeProgressBar(playing.Position, playing.Duration, 15))
-			chatbox.Placeholder("media.player", player)
-
-			break
+		if playing.URL != "" {
+			chatbox.StickyPlaceholder("media.url", playing.URL, mediaTTL)
+		} else {
+			chatbox.RemovePlaceholder("media.url")
 		}
+		if playing.UserRating > 0 {
+			chatbox.StickyPlaceholder("media.rating", m.makeRating(playing.UserRating), mediaTTL)
+		} else {
+			chatbox.RemovePlaceholder("media.rating")
+		}
+		m.registerProgress(chatbox, playing.Position, playing.Duration)
+		chatbox.StickyPlaceholder("media.player", m.container.selector.Identity(player), mediaTTL)
+		chatbox.StickyPlaceholder("media.player.id", player, mediaTTL)
+	}
 
+	if status != "Playing" {
+		for _, placeholder := range mediaTrackPlaceholders {
+			chatbox.RemovePlaceholder(placeholder)
+		}
 	}
+	chatbox.StickyPlaceholder("media.status", m.container.locale.T(status), mediaTTL)
 
 	return nil
 }
@@ -72,27 +146,40 @@ func (m MediaChatBoxModule) formatDuration(dur time.Duration) string {
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
 
+// registerProgress publishes the position related placeholders, they are extrapolated from the
+// time of the measurement so they keep moving between ticks.
+func (m MediaChatBoxModule) registerProgress(chatbox *chatbox.ChatBoxBuilder, position time.Duration, duration time.Duration) {
+	measured := time.Now()
+	current := func() time.Duration {
+		return min(position+time.Since(measured), duration)
+	}
+
+	chatbox.StickyPlaceholderFunc("media.progress", func() string {
+		return m.makeProgressBar(current(), duration, m.container.config.ProgressBarWidth)
+	}, mediaTTL)
+	chatbox.StickyPlaceholderFunc("media.position", func() string {
+		return m.formatDuration(current())
+	}, mediaTTL)
+	chatbox.StickyPlaceholder("media.duration", m.formatDuration(duration), mediaTTL)
+	chatbox.StickyPlaceholderFunc("media.percent", func() string {
+		if duration <= 0 {
+			return "0"
+		}
+		return strconv.Itoa(int(float64(current()) / float64(duration) * 100))
+	}, mediaTTL)
+}
+
+func (m MediaChatBoxModule) makeRating(rating float64) string {
+	stars := int(math.Round(min(rating, 1) * 5))
+	return strings.Repeat("★", stars) + strings.Repeat("☆", 5-stars)
+}
+
 func (m MediaChatBoxModule) makeProgressBar(position time.Duration, duration time.Duration, width int) string {
 	ratio := float64(position) / float64(duration)
-	filled := int(ratio * float64(width))
 
-	if filled > width {
-		filled = width
-	}
-
-	bar := fmt.Sprintf("|%s%s| %s / %s",
-		strings.Repeat("x", m.max(filled, 0)),
-		strings.Repeat("-", m.max(width-filled, 0)),
+	return fmt.Sprintf("%s %s / %s",
+		chatbox.ProgressBar(ratio, width, m.container.config.ProgressBarStyle),
 		m.formatDuration(position),
 		m.formatDuration(duration),
 	)
-
-	return bar
-}
-
-func (m MediaChatBoxModule) max(a int, b int) int {
-	if a > b {
-		return a
-	}
-	return b
 }
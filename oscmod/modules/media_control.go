@@ -2,27 +2,40 @@ package modules
 
 import (
 	"log"
+	"sync"
 
 	"github.com/Glowman554/OpenOSC/mpris"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/hypebeast/go-osc/osc"
 )
 
+// mediaControlSent are the parameters last sent to the avatar, they are only sent again when they
+// change and the avatar echoing them back is not applied to the player.
+type mediaControlSent struct {
+	player string
+	volume float32
+	muted  bool
+	rate   float32
+}
+
 type MediaControlModuleContainer struct {
+	selector       *mpris.Selector
 	dbus           *mpris.DBUSInterface
-	currentPlayer  *string
 	seekToPosition float32
+
+	sent  *mediaControlSent
+	mutex sync.Mutex
 }
 
 type MediaControlModule struct {
 	container *MediaControlModuleContainer
 }
 
-func NewMediaControlModule() MediaControlModule {
+func NewMediaControlModule(selector *mpris.Selector) MediaControlModule {
 	return MediaControlModule{
 		container: &MediaControlModuleContainer{
-			dbus:          &mpris.DBUSInterface{},
-			currentPlayer: nil,
+			selector: selector,
+			dbus:     selector.Watcher().DBus(),
 		},
 	}
 }
@@ -35,25 +48,49 @@ func (m MediaControlModule) Id() string {
 	return "media_control"
 }
 
+func (m MediaControlModule) ConfigSection() string {
+	return ""
+}
+
+func (m MediaControlModule) Addresses() []string {
+	return []string{
+		"/avatar/parameters/VRCOSC/Media/Play",
+		"/avatar/parameters/VRCOSC/Media/Next",
+		"/avatar/parameters/VRCOSC/Media/Previous",
+		"/avatar/parameters/VRCOSC/Media/Repeat",
+		"/avatar/parameters/VRCOSC/Media/Shuffle",
+		"/avatar/parameters/VRCOSC/Media/Seeking",
+		"/avatar/parameters/VRCOSC/Media/Position",
+		"/avatar/parameters/VRCOSC/Media/CyclePlayer",
+		"/avatar/parameters/VRCOSC/Media/Volume",
+		"/avatar/parameters/VRCOSC/Media/Muted",
+		"/avatar/parameters/VRCOSC/Media/Rate",
+	}
+}
+
+func (m MediaControlModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{
+		{Name: "media.control.player", Description: "Name of the player controlled by the avatar", Example: "Spotify"},
+	}
+}
+
 func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
-	err := m.container.dbus.Connect()
+	err := m.container.selector.Watcher().Start()
 	if err != nil {
 		return err
 	}
 
-	// VRCOSC/Media/Muted // idk?
-	// VRCOSC/Media/Volume // maybe?
-
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Play", func(msg *osc.Message) {
 		if play, ok := msg.Arguments[0].(bool); ok {
-			if m.container.currentPlayer == nil {
+			player, ok := m.currentPlayer()
+			if !ok {
 				return
 			}
 
 			if play {
-				m.container.dbus.Play(*m.container.currentPlayer)
+				m.container.dbus.Play(player)
 			} else {
-				m.container.dbus.Pause(*m.container.currentPlayer)
+				m.container.dbus.Pause(player)
 			}
 		}
 	})
@@ -63,10 +100,11 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Next", func(msg *osc.Message) {
 		if next, ok := msg.Arguments[0].(bool); ok && next {
-			if m.container.currentPlayer == nil {
+			player, ok := m.currentPlayer()
+			if !ok {
 				return
 			}
-			m.container.dbus.Next(*m.container.currentPlayer)
+			m.container.dbus.Next(player)
 		}
 	})
 	if err != nil {
@@ -75,10 +113,11 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Previous", func(msg *osc.Message) {
 		if previous, ok := msg.Arguments[0].(bool); ok && previous {
-			if m.container.currentPlayer == nil {
+			player, ok := m.currentPlayer()
+			if !ok {
 				return
 			}
-			m.container.dbus.Previous(*m.container.currentPlayer)
+			m.container.dbus.Previous(player)
 		}
 	})
 	if err != nil {
@@ -87,17 +126,18 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Repeat", func(msg *osc.Message) {
 		if mode, ok := msg.Arguments[0].(int32); ok {
-			if m.container.currentPlayer == nil {
+			player, ok := m.currentPlayer()
+			if !ok {
 				return
 			}
 
 			switch mode {
 			case 0: // No repeat
-				m.container.dbus.Loop(*m.container.currentPlayer, mpris.None)
+				m.container.dbus.Loop(player, mpris.None)
 			case 1: // Repeat track
-				m.container.dbus.Loop(*m.container.currentPlayer, mpris.Track)
+				m.container.dbus.Loop(player, mpris.Track)
 			case 2: // Repeat playlist
-				m.container.dbus.Loop(*m.container.currentPlayer, mpris.Playlist)
+				m.container.dbus.Loop(player, mpris.Playlist)
 			}
 		}
 	})
@@ -107,11 +147,12 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Shuffle", func(msg *osc.Message) {
 		if shuffle, ok := msg.Arguments[0].(bool); ok {
-			if m.container.currentPlayer == nil {
+			player, ok := m.currentPlayer()
+			if !ok {
 				return
 			}
 
-			m.container.dbus.Shuffle(*m.container.currentPlayer, shuffle)
+			m.container.dbus.Shuffle(player, shuffle)
 		}
 	})
 	if err != nil {
@@ -121,11 +162,12 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Seeking", func(msg *osc.Message) {
 		if seek, ok := msg.Arguments[0].(bool); ok {
 			if !seek {
-				if m.container.currentPlayer == nil {
+				player, ok := m.currentPlayer()
+				if !ok {
 					return
 				}
 
-				m.container.dbus.Seek(*m.container.currentPlayer, m.container.seekToPosition)
+				m.container.dbus.Seek(player, m.container.seekToPosition)
 			}
 		}
 	})
@@ -142,74 +184,204 @@ func (m MediaControlModule) Init(client *osc.Client, dispatcher *osc.StandardDis
 		return err
 	}
 
+	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Volume", func(msg *osc.Message) {
+		if volume, ok := msg.Arguments[0].(float32); ok {
+			player, ok := m.currentPlayer()
+			if !ok || m.echoed(player, func(sent *mediaControlSent) bool { return sent.volume == volume }) {
+				return
+			}
+
+			m.container.dbus.Volume(player, float64(volume))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Muted", func(msg *osc.Message) {
+		if muted, ok := msg.Arguments[0].(bool); ok {
+			player, ok := m.currentPlayer()
+			if !ok || m.echoed(player, func(sent *mediaControlSent) bool { return sent.muted == muted }) {
+				return
+			}
+
+			m.container.dbus.Mute(player, muted)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/Rate", func(msg *osc.Message) {
+		if rate, ok := msg.Arguments[0].(float32); ok {
+			player, ok := m.currentPlayer()
+			if !ok || m.echoed(player, func(sent *mediaControlSent) bool { return sent.rate == rate }) {
+				return
+			}
+
+			m.container.dbus.Rate(player, m.parameterToRate(rate))
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/Media/CyclePlayer", func(msg *osc.Message) {
+		if cycle, ok := msg.Arguments[0].(bool); ok && cycle {
+			if player, ok := m.container.selector.Cycle(); ok {
+				log.Printf("Controlling %s", m.container.selector.Identity(player))
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (m MediaControlModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
-	players, err := m.container.dbus.LoadPlayers()
-	if err != nil {
-		return err
+	player, playing, ok := m.container.selector.Current()
+	if !ok {
+		return nil
 	}
 
-	for _, player := range players {
-		playing, err := m.container.dbus.LoadCurrentlyPlaying(player)
+	if playing.Status == mpris.Playing {
+		ratio := float32(playing.Position) / float32(playing.Duration)
+
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Position")
+		msg.Append(ratio)
+		err := client.Send(msg)
 		if err != nil {
+			log.Printf("Failed to send message: %v", err)
 			return err
 		}
+	}
 
+	if playing.Status == mpris.Playing || playing.Status == mpris.Paused {
+		chatbox.Placeholder("media.control.player", m.container.selector.Identity(player))
+
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Play")
 		if playing.Status == mpris.Playing {
-			ratio := float32(playing.Position) / float32(playing.Duration)
-
-			msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Position")
-			msg.Append(ratio)
-			err := client.Send(msg)
-			if err != nil {
-				log.Printf("Failed to send message: %v", err)
-				return err
-			}
+			msg.Append(true)
+		} else {
+			msg.Append(false)
+		}
+		err := client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
 		}
 
-		m.container.currentPlayer = nil
-		if playing.Status == mpris.Playing || playing.Status == mpris.Paused {
-			m.container.currentPlayer = &player
-			chatbox.Placeholder("media.control.player", player)
+		msg = osc.NewMessage("/avatar/parameters/VRCOSC/Media/Repeat")
+		msg.Append(m.loopTypeToId(playing.Loop))
+		err = client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
 
-			msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Play")
-			if playing.Status == mpris.Playing {
-				msg.Append(true)
-			} else {
-				msg.Append(false)
-			}
-			err := client.Send(msg)
-			if err != nil {
-				log.Printf("Failed to send message: %v", err)
-				return err
-			}
+		msg = osc.NewMessage("/avatar/parameters/VRCOSC/Media/Shuffle")
+		msg.Append(playing.Shuffle)
+		err = client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
 
-			msg = osc.NewMessage("/avatar/parameters/VRCOSC/Media/Repeat")
-			msg.Append(m.loopTypeToId(playing.Loop))
-			err = client.Send(msg)
-			if err != nil {
-				log.Printf("Failed to send message: %v", err)
-				return err
-			}
+		err = m.sendPlayerState(client, player, playing)
+		if err != nil {
+			return err
+		}
+	}
 
-			msg = osc.NewMessage("/avatar/parameters/VRCOSC/Media/Shuffle")
-			msg.Append(playing.Shuffle)
-			err = client.Send(msg)
-			if err != nil {
-				log.Printf("Failed to send message: %v", err)
-				return err
-			}
+	return nil
+}
+
+// sendPlayerState sends volume, mute and rate when they or the player changed. The state is stored
+// before sending so the echo of the avatar is recognized, it is forgotten again if sending fails.
+func (m MediaControlModule) sendPlayerState(client *osc.Client, player string, playing *mpris.CurrentlyPlaying) (err error) {
+	state := mediaControlSent{
+		player: player,
+		volume: float32(min(playing.Volume, 1)),
+		muted:  playing.Volume == 0,
+		rate:   m.rateToParameter(playing.Rate),
+	}
+
+	m.container.mutex.Lock()
+	sent := m.container.sent
+	m.container.sent = &state
+	m.container.mutex.Unlock()
 
-			break
+	if sent != nil && sent.player != player {
+		sent = nil
+	}
+
+	defer func() {
+		if err != nil {
+			m.container.mutex.Lock()
+			m.container.sent = nil
+			m.container.mutex.Unlock()
 		}
+	}()
 
+	if sent == nil || sent.volume != state.volume {
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Volume")
+		msg.Append(state.volume)
+		err := client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
+	}
+
+	if sent == nil || sent.muted != state.muted {
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Muted")
+		msg.Append(state.muted)
+		err := client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
+	}
+
+	if sent == nil || sent.rate != state.rate {
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Media/Rate")
+		msg.Append(state.rate)
+		err := client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
 	}
 
 	return nil
 }
 
+// echoed reports whether a parameter only repeats what was last sent for player.
+func (m MediaControlModule) echoed(player string, same func(sent *mediaControlSent) bool) bool {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	return m.container.sent != nil && m.container.sent.player == player && same(m.container.sent)
+}
+
+// currentPlayer is looked up when a parameter changes so the avatar always controls the player
+// that is selected right now.
+func (m MediaControlModule) currentPlayer() (string, bool) {
+	player, _, ok := m.container.selector.Current()
+	return player, ok
+}
+
+// parameterToRate maps the 0 to 1 avatar parameter onto 0x to 2x, the default 0.5 is normal speed.
+func (m MediaControlModule) parameterToRate(parameter float32) float64 {
+	return float64(parameter) * 2
+}
+
+func (m MediaControlModule) rateToParameter(rate float64) float32 {
+	return float32(max(min(rate/2, 1), 0))
+}
+
 func (m MediaControlModule) loopTypeToId(loop mpris.LoopType) int32 {
 	switch loop {
 	case mpris.None:
@@ -0,0 +1,225 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/mpris"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/hypebeast/go-osc/osc"
+)
+
+// MediaHistoryEntry is a played track, it is also the format of the JSONL listening log.
+type MediaHistoryEntry struct {
+	Title           string    `json:"title"`
+	Artist          []string  `json:"artist"`
+	Album           string    `json:"album"`
+	Player          string    `json:"player"`
+	PlayerName      string    `json:"playerName"`
+	Started         time.Time `json:"started"`
+	DurationSeconds int       `json:"durationSeconds"`
+	ListenedSeconds int       `json:"listenedSeconds"`
+}
+
+func (e *MediaHistoryEntry) sameTrack(player string, playing *mpris.CurrentlyPlaying) bool {
+	return e.Player == player && e.Title == playing.Title && e.Album == playing.Album && slices.Equal(e.Artist, playing.Artist)
+}
+
+type MediaHistoryModuleContainer struct {
+	selector *mpris.Selector
+
+	// current is the track that is playing or paused right now.
+	current  *MediaHistoryEntry
+	listened time.Duration
+	lastTick time.Time
+
+	history      []MediaHistoryEntry
+	sessionCount int
+	sessionTime  time.Duration
+
+	config config.MediaHistoryConfig
+	mutex  sync.Mutex
+}
+
+type MediaHistoryModule struct {
+	container *MediaHistoryModuleContainer
+}
+
+func NewMediaHistoryModule(selector *mpris.Selector, config config.MediaHistoryConfig) MediaHistoryModule {
+	return MediaHistoryModule{
+		container: &MediaHistoryModuleContainer{
+			selector: selector,
+			history:  []MediaHistoryEntry{},
+			config:   config,
+		},
+	}
+}
+
+func (m MediaHistoryModule) Name() string {
+	return "Media history"
+}
+
+func (m MediaHistoryModule) Id() string {
+	return "media_history"
+}
+
+func (m MediaHistoryModule) ConfigSection() string {
+	return "mediaHistory"
+}
+
+func (m MediaHistoryModule) Addresses() []string {
+	return []string{}
+}
+
+func (m MediaHistoryModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{
+		{Name: "media.previous.title", Description: "Title of the previously played track", Example: "Take On Me"},
+		{Name: "media.previous.artist", Description: "Artists of the previously played track", Example: "a-ha"},
+		{Name: "media.previous.album", Description: "Album of the previously played track", Example: "Hunting High and Low"},
+		{Name: "media.previous.player", Description: "Name of the player of the previous track", Example: "Spotify"},
+		{Name: "media.session.count", Description: "Number of tracks played since OpenOSC started", Example: "12"},
+		{Name: "media.session.time", Description: "Time spent listening since OpenOSC started", Example: "00:42:17"},
+	}
+}
+
+func (m MediaHistoryModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
+	return m.container.selector.Watcher().Start()
+}
+
+func (m MediaHistoryModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
+	m.update(time.Now())
+
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	if len(m.container.history) > 0 {
+		previous := m.container.history[0]
+		chatbox.Placeholder("media.previous.title", previous.Title)
+		chatbox.Placeholder("media.previous.artist", strings.Join(previous.Artist, ", "))
+		chatbox.Placeholder("media.previous.album", previous.Album)
+		chatbox.Placeholder("media.previous.player", previous.PlayerName)
+	}
+	chatbox.Placeholder("media.session.count", strconv.Itoa(m.container.sessionCount))
+	chatbox.Placeholder("media.session.time", m.formatSessionTime(m.container.sessionTime))
+
+	return nil
+}
+
+// update follows the track of the selected player, a track ends when another one starts playing
+// or its player stops. Paused tracks are kept without counting the listening time.
+func (m MediaHistoryModule) update(now time.Time) {
+	var playing *mpris.CurrentlyPlaying
+	paused := false
+
+	playingPlayer, track, ok := m.container.selector.Current()
+
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	current := m.container.current
+	if ok && track.Status == mpris.Playing {
+		playing = track
+	}
+	if ok && track.Status == mpris.Paused && current != nil && current.sameTrack(playingPlayer, track) {
+		paused = true
+	}
+
+	elapsed := time.Duration(0)
+	if !m.container.lastTick.IsZero() {
+		elapsed = now.Sub(m.container.lastTick)
+	}
+	m.container.lastTick = now
+
+	if playing != nil && current != nil && current.sameTrack(playingPlayer, playing) {
+		m.container.listened += elapsed
+		m.container.sessionTime += elapsed
+		return
+	}
+
+	if playing == nil && paused {
+		return
+	}
+
+	m.finish()
+
+	if playing != nil {
+		m.container.current = &MediaHistoryEntry{
+			Title:           playing.Title,
+			Artist:          playing.Artist,
+			Album:           playing.Album,
+			Player:          playingPlayer,
+			PlayerName:      m.container.selector.Identity(playingPlayer),
+			Started:         now,
+			DurationSeconds: int(playing.Duration.Seconds()),
+		}
+		m.container.listened = 0
+	}
+}
+
+// finish records the current track if it was listened to for long enough.
+func (m MediaHistoryModule) finish() {
+	current := m.container.current
+	m.container.current = nil
+	if current == nil || m.container.listened < time.Duration(m.container.config.MinimumListenSeconds)*time.Second {
+		return
+	}
+
+	current.ListenedSeconds = int(m.container.listened.Seconds())
+	m.container.sessionCount++
+	m.container.history = slices.Insert(m.container.history, 0, *current)
+	if len(m.container.history) > m.container.config.HistorySize {
+		m.container.history = m.container.history[:m.container.config.HistorySize]
+	}
+
+	if m.container.config.LogPath != "" {
+		err := m.appendLog(*current)
+		if err != nil {
+			log.Printf("Failed to write listening log: %v", err)
+		}
+	}
+}
+
+func (m MediaHistoryModule) appendLog(entry MediaHistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(m.container.config.LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+func (m MediaHistoryModule) formatSessionTime(duration time.Duration) string {
+	hours := int(duration.Hours())
+	minutes := int(duration.Minutes()) % 60
+	seconds := int(duration.Seconds()) % 60
+	return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+}
+
+func (m MediaHistoryModule) Status() []string {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	status := []string{fmt.Sprintf("session: %d tracks, %s", m.container.sessionCount, m.formatSessionTime(m.container.sessionTime))}
+	if m.container.current != nil {
+		status = append(status, fmt.Sprintf("now: %s - %s", m.container.current.Title, strings.Join(m.container.current.Artist, ", ")))
+	}
+	for _, entry := range m.container.history {
+		status = append(status, fmt.Sprintf("%s %s - %s", entry.Started.Format("15:04"), entry.Title, strings.Join(entry.Artist, ", ")))
+	}
+	return status
+}
@@ -0,0 +1,259 @@
+package modules
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/locale"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/godbus/dbus/v5"
+	"github.com/hypebeast/go-osc/osc"
+)
+
+var notificationMarkup = regexp.MustCompile(`<[^>]*>`)
+
+const (
+	// maxPendingNotifications limits the notifications kept between ticks, older ones are dropped.
+	maxPendingNotifications = 16
+	// maxQueuedNotifications is the chatbox queue length up to which notifications are added as
+	// messages, so a burst of notifications does not hold back the other lines and messages.
+	maxQueuedNotifications = 3
+)
+
+type notification struct {
+	app      string
+	summary  string
+	body     string
+	received time.Time
+}
+
+type NotificationsModuleContainer struct {
+	// pending notifications are shown on the next tick.
+	pending []notification
+	last    *notification
+	paused  bool
+	mutex   sync.Mutex
+
+	redact  []*regexp.Regexp
+	message *chatbox.Template
+	config  config.NotificationsConfig
+	locale  *locale.Locale
+}
+
+type NotificationsModule struct {
+	container *NotificationsModuleContainer
+}
+
+func NewNotificationsModule(config config.NotificationsConfig, locale *locale.Locale) NotificationsModule {
+	return NotificationsModule{
+		container: &NotificationsModuleContainer{
+			pending: []notification{},
+			redact:  []*regexp.Regexp{},
+			config:  config,
+			locale:  locale,
+		},
+	}
+}
+
+func (m NotificationsModule) Name() string {
+	return "Notifications"
+}
+
+func (m NotificationsModule) Id() string {
+	return "notifications"
+}
+
+func (m NotificationsModule) ConfigSection() string {
+	return "notifications"
+}
+
+func (m NotificationsModule) Addresses() []string {
+	return []string{}
+}
+
+func (m NotificationsModule) Placeholders() []chatbox.PlaceholderInfo {
+	ttl := time.Duration(m.container.config.DisplaySeconds) * time.Second
+	return []chatbox.PlaceholderInfo{
+		{Name: "notification.app", Description: "App of the latest allowed desktop notification", Example: "discord", TTL: ttl},
+		{Name: "notification.summary", Description: "Summary of the latest notification, redacted", Example: "New message", TTL: ttl},
+		{Name: "notification.body", Description: "Body of the latest notification, redacted", Example: "see you in VRChat", TTL: ttl},
+	}
+}
+
+func (m NotificationsModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
+	for _, pattern := range m.container.config.Redact {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+		m.container.redact = append(m.container.redact, expression)
+	}
+
+	if m.container.config.Message != "" {
+		message, err := chatbox.ParseTemplate(m.container.config.Message)
+		if err != nil {
+			return err
+		}
+		m.container.message = message
+	}
+
+	// a monitor connection can not be used for anything else, so the shared session bus
+	// connection of the media modules is not used
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		log.Printf("Failed to connect to session bus: %v", err)
+		return err
+	}
+
+	rules := []string{"type='method_call',interface='org.freedesktop.Notifications',member='Notify'"}
+	call := conn.BusObject().Call("org.freedesktop.DBus.Monitoring.BecomeMonitor", 0, rules, uint32(0))
+	if call.Err != nil {
+		log.Printf("Failed to monitor notifications: %v", call.Err)
+		conn.Close()
+		return call.Err
+	}
+
+	messages := make(chan *dbus.Message, 16)
+	conn.Eavesdrop(messages)
+	go m.run(messages)
+
+	return nil
+}
+
+func (m NotificationsModule) run(messages chan *dbus.Message) {
+	for message := range messages {
+		notification, ok := m.parse(message)
+		if !ok || !m.allowed(notification.app) {
+			continue
+		}
+
+		m.container.mutex.Lock()
+		if !m.container.paused {
+			m.container.pending = append(m.container.pending, notification)
+			if len(m.container.pending) > maxPendingNotifications {
+				m.container.pending = m.container.pending[len(m.container.pending)-maxPendingNotifications:]
+			}
+		}
+		m.container.mutex.Unlock()
+	}
+}
+
+// parse reads the Notify(app_name, replaces_id, app_icon, summary, body, ...) arguments.
+func (m NotificationsModule) parse(message *dbus.Message) (notification, bool) {
+	if message.Type != dbus.TypeMethodCall || len(message.Body) < 5 {
+		return notification{}, false
+	}
+
+	app, ok1 := message.Body[0].(string)
+	summary, ok2 := message.Body[3].(string)
+	body, ok3 := message.Body[4].(string)
+	if !ok1 || !ok2 || !ok3 {
+		return notification{}, false
+	}
+
+	return notification{
+		app:      app,
+		summary:  m.clean(summary),
+		body:     m.clean(body),
+		received: time.Now(),
+	}, true
+}
+
+func (m NotificationsModule) allowed(app string) bool {
+	return slices.ContainsFunc(m.container.config.AllowedApps, func(allowed string) bool {
+		return strings.EqualFold(allowed, app)
+	})
+}
+
+// clean strips the markup some apps send and redacts the text, it is done before the text is
+// stored so private content never reaches the chatbox or the TUI.
+func (m NotificationsModule) clean(text string) string {
+	text = notificationMarkup.ReplaceAllString(text, "")
+	text = strings.Join(strings.Fields(text), " ")
+	for _, expression := range m.container.redact {
+		text = expression.ReplaceAllString(text, "***")
+	}
+	return text
+}
+
+func (m NotificationsModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
+	m.container.mutex.Lock()
+	pending := m.container.pending
+	m.container.pending = []notification{}
+	if len(pending) > 0 {
+		m.container.last = &pending[len(pending)-1]
+	}
+	m.container.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ttl := time.Duration(m.container.config.DisplaySeconds) * time.Second
+	latest := pending[len(pending)-1]
+	chatbox.StickyPlaceholder("notification.app", latest.app, ttl)
+	chatbox.StickyPlaceholder("notification.summary", latest.summary, ttl)
+	chatbox.StickyPlaceholder("notification.body", latest.body, ttl)
+
+	if m.container.message == nil {
+		return nil
+	}
+
+	// only the latest notifications are queued when there is not enough room for all of them
+	free := max(maxQueuedNotifications-chatbox.QueuedMessages(), 0)
+	if len(pending) > free {
+		log.Printf("Skipped %d notifications, the chatbox queue is full", len(pending)-free)
+		pending = pending[len(pending)-free:]
+	}
+
+	for _, notification := range pending {
+		err := chatbox.Message(m.renderMessage(notification), ttl, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderMessage fills in the notification right away so queued messages keep their own text, the
+// result is escaped since chatbox messages are templates themselves.
+func (m NotificationsModule) renderMessage(notification notification) string {
+	values := map[string]string{
+		"notification.app":     notification.app,
+		"notification.summary": notification.summary,
+		"notification.body":    notification.body,
+	}
+
+	text, _ := m.container.message.Render(func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok && value != ""
+	}, m.container.locale)
+
+	text = strings.ReplaceAll(text, "{", "{{")
+	return strings.ReplaceAll(text, "}", "}}")
+}
+
+func (m NotificationsModule) SetPaused(paused bool) {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	m.container.paused = paused
+	m.container.pending = []notification{}
+}
+
+func (m NotificationsModule) Status() []string {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	if m.container.last == nil {
+		return []string{"no notifications yet"}
+	}
+	return []string{fmt.Sprintf("last: %s at %s", m.container.last.app, m.container.last.received.Format("15:04:05"))}
+}
@@ -3,9 +3,13 @@ package modules
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Glowman554/OpenOSC/config"
 	"github.com/Glowman554/OpenOSC/openshock"
+	"github.com/Glowman554/OpenOSC/oscmod"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/hypebeast/go-osc/osc"
 )
@@ -22,6 +26,10 @@ type OpenShockModuleContainer struct {
 
 	config config.OpenShockConfig
 	api    *openshock.OpenShockApi
+
+	paused atomic.Bool
+	// mutex guards the current group and the duration and intensity of the groups.
+	mutex sync.Mutex
 }
 
 type OpenShockModule struct {
@@ -47,6 +55,25 @@ func (m OpenShockModule) Id() string {
 	return "openshock"
 }
 
+func (m OpenShockModule) ConfigSection() string {
+	return "openShockConfig"
+}
+
+func (m OpenShockModule) Addresses() []string {
+	addresses := []string{"/avatar/parameters/VRCOSC/PiShock/Group"}
+	for _, action := range []string{"Duration", "Intensity", "Shock", "Vibrate", "Beep"} {
+		addresses = append(addresses,
+			"/avatar/parameters/VRCOSC/PiShock/"+action,
+			"/avatar/parameters/VRCOSC/PiShock/"+action+"/0",
+		)
+	}
+	return addresses
+}
+
+func (m OpenShockModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{}
+}
+
 func (m OpenShockModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
 	shockers, err := m.container.api.LoadShockers()
 	if err != nil {
@@ -67,7 +94,9 @@ func (m OpenShockModule) Init(client *osc.Client, dispatcher *osc.StandardDispat
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Group", func(msg *osc.Message) {
 		if group, ok := msg.Arguments[0].(int32); ok {
 			if _, ok := m.container.groups[fmt.Sprint(group)]; ok {
+				m.container.mutex.Lock()
 				m.container.currentDefaultGroup = fmt.Sprint(group)
+				m.container.mutex.Unlock()
 				log.Printf("Setting group to %d", group)
 			} else {
 				log.Printf("Invalid group %d", group)
@@ -79,35 +108,35 @@ func (m OpenShockModule) Init(client *osc.Client, dispatcher *osc.StandardDispat
 	}
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Duration", func(msg *osc.Message) {
-		m.container.groups[m.container.currentDefaultGroup].handleDuration(msg, m)
+		m.currentGroup().handleDuration(msg, m)
 	})
 	if err != nil {
 		return err
 	}
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Intensity", func(msg *osc.Message) {
-		m.container.groups[m.container.currentDefaultGroup].handleIntensity(msg, m)
+		m.currentGroup().handleIntensity(msg, m)
 	})
 	if err != nil {
 		return err
 	}
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Shock", func(msg *osc.Message) {
-		m.container.groups[m.container.currentDefaultGroup].handleShock(msg, client, m)
+		m.currentGroup().handleShock(msg, client, m)
 	})
 	if err != nil {
 		return err
 	}
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Vibrate", func(msg *osc.Message) {
-		m.container.groups[m.container.currentDefaultGroup].handleVibrate(msg, client, m)
+		m.currentGroup().handleVibrate(msg, client, m)
 	})
 	if err != nil {
 		return err
 	}
 
 	err = dispatcher.AddMsgHandler("/avatar/parameters/VRCOSC/PiShock/Beep", func(msg *osc.Message) {
-		m.container.groups[m.container.currentDefaultGroup].handleBeep(msg, client, m)
+		m.currentGroup().handleBeep(msg, client, m)
 	})
 	if err != nil {
 		return err
@@ -124,6 +153,48 @@ func (m OpenShockModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilde
 	return nil
 }
 
+// currentGroup returns the group the unnumbered parameters control.
+func (m OpenShockModule) currentGroup() *OpenShockGroup {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+	return m.container.groups[m.container.currentDefaultGroup]
+}
+
+func (m OpenShockModule) SetPaused(paused bool) {
+	m.container.paused.Store(paused)
+}
+
+func (m OpenShockModule) Status() []string {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	groupIDs := []string{}
+	for id := range m.container.groups {
+		groupIDs = append(groupIDs, id)
+	}
+	slices.Sort(groupIDs)
+
+	status := []string{}
+	for _, id := range groupIDs {
+		group := m.container.groups[id]
+		marker := " "
+		if id == m.container.currentDefaultGroup {
+			marker = "*"
+		}
+		status = append(status, fmt.Sprintf("%s group %s: %d shockers, %dms, %d%%", marker, id, len(group.shockerIDs), group.currentDuration, group.currentIntensity))
+	}
+	return status
+}
+
+// blocked reports whether actions should be dropped because the module is paused or stopped.
+func (m OpenShockModule) blocked() bool {
+	if m.container.paused.Load() || oscmod.IsEmergencyStopped() {
+		log.Println("Ignoring OpenShock action (module paused or emergency stop engaged)")
+		return true
+	}
+	return false
+}
+
 func (m OpenShockModule) registerGroup(groupID string, shockerIDs []string, client *osc.Client, dispatcher *osc.StandardDispatcher) error {
 	group := &OpenShockGroup{
 		shockerIDs:       shockerIDs,
@@ -172,6 +243,8 @@ func (m OpenShockModule) registerGroup(groupID string, shockerIDs []string, clie
 
 func (g *OpenShockGroup) handleDuration(msg *osc.Message, m OpenShockModule) {
 	if duration, ok := msg.Arguments[0].(float32); ok {
+		m.container.mutex.Lock()
+		defer m.container.mutex.Unlock()
 		g.currentDuration = int(float32(m.container.config.MaximumDurationMS) * duration)
 		// log.Printf("Duration: %dms", g.currentDuration)
 	}
@@ -179,29 +252,53 @@ func (g *OpenShockGroup) handleDuration(msg *osc.Message, m OpenShockModule) {
 
 func (g *OpenShockGroup) handleIntensity(msg *osc.Message, m OpenShockModule) {
 	if intensity, ok := msg.Arguments[0].(float32); ok {
+		m.container.mutex.Lock()
+		defer m.container.mutex.Unlock()
 		g.currentIntensity = int(float32(m.container.config.MaximumIntensity) * intensity)
 		// log.Printf("Intensity: %d%%", g.currentIntensity)
 	}
 }
 
+// current returns the intensity and duration of the group.
+func (g *OpenShockGroup) current(m OpenShockModule) (int, int) {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+	return g.currentIntensity, g.currentDuration
+}
+
 func (g *OpenShockGroup) handleShock(msg *osc.Message, client *osc.Client, m OpenShockModule) {
 	if shock, ok := msg.Arguments[0].(bool); ok && shock {
-		m.container.api.SendCommand(g.currentIntensity, g.currentDuration, openshock.Shock, g.shockerIDs)
+		if m.blocked() {
+			return
+		}
+
+		intensity, duration := g.current(m)
+		m.container.api.SendCommand(intensity, duration, openshock.Shock, g.shockerIDs)
 		g.sendSuccess(client)
 	}
 }
 
 func (g *OpenShockGroup) handleVibrate(msg *osc.Message, client *osc.Client, m OpenShockModule) {
 	if vibrate, ok := msg.Arguments[0].(bool); ok && vibrate {
-		m.container.api.SendCommand(g.currentIntensity, g.currentDuration, openshock.Vibrate, g.shockerIDs)
+		if m.blocked() {
+			return
+		}
+
+		intensity, duration := g.current(m)
+		m.container.api.SendCommand(intensity, duration, openshock.Vibrate, g.shockerIDs)
 		g.sendSuccess(client)
 	}
 }
 
 func (g *OpenShockGroup) handleBeep(msg *osc.Message, client *osc.Client, m OpenShockModule) {
 	if beep, ok := msg.Arguments[0].(bool); ok && beep {
+		if m.blocked() {
+			return
+		}
+
 		// Should BEEP but i don't want it too
-		m.container.api.SendCommand(g.currentIntensity, g.currentDuration, openshock.Vibrate, g.shockerIDs)
+		intensity, duration := g.current(m)
+		m.container.api.SendCommand(intensity, duration, openshock.Vibrate, g.shockerIDs)
 		g.sendSuccess(client)
 	}
 }
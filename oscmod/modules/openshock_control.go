@@ -3,9 +3,13 @@ package modules
 import (
 	"fmt"
 	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
 
 	"github.com/Glowman554/OpenOSC/config"
 	"github.com/Glowman554/OpenOSC/openshock"
+	"github.com/Glowman554/OpenOSC/oscmod"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
 	"github.com/hypebeast/go-osc/osc"
 )
@@ -16,6 +20,10 @@ type OpenShockControlModuleContainer struct {
 
 	currentDuration  int
 	currentIntensity int
+	// mutex guards the current duration and intensity.
+	mutex sync.Mutex
+
+	paused atomic.Bool
 }
 
 type OpenShockControlModule struct {
@@ -41,6 +49,26 @@ func (m OpenShockControlModule) Id() string {
 	return "openshock_control"
 }
 
+func (m OpenShockControlModule) ConfigSection() string {
+	return "openShockControlConfig"
+}
+
+func (m OpenShockControlModule) Addresses() []string {
+	addresses := []string{
+		m.container.config.DurationParameter,
+		m.container.config.IntensityParameter,
+	}
+	for key := range m.container.config.Mapping {
+		addresses = append(addresses, key)
+	}
+	slices.Sort(addresses[2:])
+	return addresses
+}
+
+func (m OpenShockControlModule) Placeholders() []chatbox.PlaceholderInfo {
+	return []chatbox.PlaceholderInfo{}
+}
+
 func (m OpenShockControlModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
 	shockers, err := m.container.api.LoadShockersShared()
 	if err != nil {
@@ -49,6 +77,8 @@ func (m OpenShockControlModule) Init(client *osc.Client, dispatcher *osc.Standar
 
 	err = dispatcher.AddMsgHandler(m.container.config.DurationParameter, func(msg *osc.Message) {
 		if duration, ok := msg.Arguments[0].(float32); ok {
+			m.container.mutex.Lock()
+			defer m.container.mutex.Unlock()
 			m.container.currentDuration = int(float32(m.container.config.MaximumDurationMS) * duration)
 			// log.Printf("Duration: %dms", m.container.currentDuration)
 		}
@@ -59,6 +89,8 @@ func (m OpenShockControlModule) Init(client *osc.Client, dispatcher *osc.Standar
 
 	err = dispatcher.AddMsgHandler(m.container.config.IntensityParameter, func(msg *osc.Message) {
 		if intensity, ok := msg.Arguments[0].(float32); ok {
+			m.container.mutex.Lock()
+			defer m.container.mutex.Unlock()
 			m.container.currentIntensity = int(float32(m.container.config.MaximumIntensity) * intensity)
 			// log.Printf("Intensity: %d%%", m.container.currentIntensity)
 		}
@@ -82,7 +114,13 @@ func (m OpenShockControlModule) Init(client *osc.Client, dispatcher *osc.Standar
 
 		err = dispatcher.AddMsgHandler(key, func(msg *osc.Message) {
 			if trigger, ok := msg.Arguments[0].(bool); ok && trigger {
-				err := m.container.api.SendCommand(m.container.currentIntensity, m.container.currentDuration, openshock.Shock, shockerIDs)
+				if m.container.paused.Load() || oscmod.IsEmergencyStopped() {
+					log.Printf("Ignoring %s (module paused or emergency stop engaged)", msg.Address)
+					return
+				}
+
+				intensity, duration := m.current()
+				err := m.container.api.SendCommand(intensity, duration, openshock.Shock, shockerIDs)
 				if err != nil {
 					log.Printf("Failed to send command %v", err)
 				}
@@ -97,6 +135,26 @@ func (m OpenShockControlModule) Init(client *osc.Client, dispatcher *osc.Standar
 	return nil
 }
 
+// current returns the intensity and duration set by the avatar.
+func (m OpenShockControlModule) current() (int, int) {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+	return m.container.currentIntensity, m.container.currentDuration
+}
+
+func (m OpenShockControlModule) SetPaused(paused bool) {
+	m.container.paused.Store(paused)
+}
+
+func (m OpenShockControlModule) Status() []string {
+	m.container.mutex.Lock()
+	defer m.container.mutex.Unlock()
+
+	return []string{
+		fmt.Sprintf("%dms, %d%%", m.container.currentDuration, m.container.currentIntensity),
+	}
+}
+
 func (m OpenShockControlModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
 	return nil
 }
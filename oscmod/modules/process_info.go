@@ -0,0 +1,166 @@
+package modules
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/locale"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/Glowman554/OpenOSC/sysinfo"
+	"github.com/hypebeast/go-osc/osc"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+const processInfoInterval = 2 * time.Second
+
+type ProcessInfoModuleContainer struct {
+	sampler *sysInfoSampler
+	// memoryLimit is the memory usage in bytes at which the memory pressure is 1.
+	memoryLimit float64
+
+	config config.ProcessInfoConfig
+	locale *locale.Locale
+}
+
+type ProcessInfoModule struct {
+	container *ProcessInfoModuleContainer
+}
+
+func NewProcessInfoModule(config config.ProcessInfoConfig, locale *locale.Locale) ProcessInfoModule {
+	return ProcessInfoModule{
+		container: &ProcessInfoModuleContainer{
+			config: config,
+			locale: locale,
+		},
+	}
+}
+
+func (m ProcessInfoModule) Name() string {
+	return "Process information"
+}
+
+func (m ProcessInfoModule) Id() string {
+	return "process_info"
+}
+
+func (m ProcessInfoModule) ConfigSection() string {
+	return "processInfo"
+}
+
+func (m ProcessInfoModule) Addresses() []string {
+	return []string{}
+}
+
+func (m ProcessInfoModule) Placeholders() []chatbox.PlaceholderInfo {
+	placeholders := []chatbox.PlaceholderInfo{}
+	for name, executable := range m.container.config.Processes {
+		prefix := "process." + name
+		placeholders = append(placeholders,
+			chatbox.PlaceholderInfo{Name: prefix + ".cpu", Description: "CPU usage of " + executable + ", only set while it runs", Example: "23%", UpdateRate: processInfoInterval},
+			chatbox.PlaceholderInfo{Name: prefix + ".memory", Description: "Resident memory of " + executable, Example: "3.2 GiB", UpdateRate: processInfoInterval},
+			chatbox.PlaceholderInfo{Name: prefix + ".threads", Description: "Number of threads of " + executable, Example: "87", UpdateRate: processInfoInterval},
+		)
+	}
+	placeholders = append(placeholders, chatbox.PlaceholderInfo{
+		Name:        "process.error",
+		Description: "Error of the last process reading, only set while reading fails",
+		Example:     "failed to list processes: permission denied",
+		UpdateRate:  processInfoInterval,
+	})
+	return placeholders
+}
+
+func (m ProcessInfoModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
+	m.container.memoryLimit = float64(m.container.config.MemoryLimitMB) * 1024 * 1024
+	if m.container.memoryLimit == 0 {
+		memory, err := mem.VirtualMemory()
+		if err != nil {
+			log.Printf("Failed to read total memory: %v", err)
+			return err
+		}
+		m.container.memoryLimit = float64(memory.Total)
+	}
+
+	collector := sysinfo.NewProcessCollector(m.container.config.Processes)
+	m.container.sampler = newSysInfoSampler("processes", collector, processInfoInterval)
+	m.container.sampler.start()
+
+	return nil
+}
+
+func (m ProcessInfoModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
+	metrics, err := m.container.sampler.latest()
+
+	pressure := float32(0)
+	for _, metric := range metrics {
+		chatbox.Placeholder("process."+metric.Name, m.format(metric))
+
+		if metric.Name == m.container.config.MemoryProcess+".memory" {
+			pressure = float32(min(metric.Value/m.container.memoryLimit, 1))
+		}
+	}
+	if err != nil {
+		chatbox.Placeholder("process.error", err.Error())
+	}
+
+	if m.container.config.MemoryProcess != "" {
+		msg := osc.NewMessage("/avatar/parameters/VRCOSC/Process/MemoryPressure")
+		msg.Append(pressure)
+		err := client.Send(msg)
+		if err != nil {
+			log.Printf("Failed to send message: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m ProcessInfoModule) format(metric sysinfo.Metric) string {
+	switch metric.Unit {
+	case sysinfo.Percent:
+		return m.container.locale.Percent(int(math.Round(metric.Value)))
+	case sysinfo.Bytes:
+		value := metric.Value / 1024 / 1024
+		if value >= 1024 {
+			return m.container.locale.FormatNumber(value/1024, 1) + " GiB"
+		}
+		return m.container.locale.FormatNumber(value, 0) + " MiB"
+	default:
+		return strconv.Itoa(int(metric.Value))
+	}
+}
+
+func (m ProcessInfoModule) SetPaused(paused bool) {
+	m.container.sampler.setPaused(paused)
+}
+
+func (m ProcessInfoModule) Status() []string {
+	metrics, err := m.container.sampler.latest()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	running := []string{}
+	for _, metric := range metrics {
+		if name, ok := strings.CutSuffix(metric.Name, ".cpu"); ok {
+			running = append(running, name)
+		}
+	}
+	if len(running) == 0 {
+		return []string{"no watched process is running"}
+	}
+	slices.Sort(running)
+	return []string{fmt.Sprintf("running: %s", strings.Join(running, ", "))}
+}
+
+// Shutdown stops the background sampler.
+func (m ProcessInfoModule) Shutdown() {
+	m.container.sampler.shutdown()
+}
@@ -2,27 +2,38 @@ package modules
 
 import (
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/locale"
 	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/Glowman554/OpenOSC/sysinfo"
 	"github.com/hypebeast/go-osc/osc"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/mem"
 )
 
+// sysInfoTTL keeps the last measurement visible if a measurement is slow or fails.
+const sysInfoTTL = 10 * time.Second
+
 type SysInfoModuleContainer struct {
-	currentCpu    int
-	currentMemory int
+	timeZones map[string]*time.Location
+	samplers  []*sysInfoSampler
+
+	config config.SysInfoConfig
+	locale *locale.Locale
 }
 
 type SysInfoModule struct {
 	container *SysInfoModuleContainer
 }
 
-func NewSysInfoModule() SysInfoModule {
+func NewSysInfoModule(config config.SysInfoConfig, locale *locale.Locale) SysInfoModule {
 	return SysInfoModule{
-		container: &SysInfoModuleContainer{},
+		container: &SysInfoModuleContainer{
+			timeZones: map[string]*time.Location{},
+			samplers:  []*sysInfoSampler{},
+			config:    config,
+			locale:    locale,
+		},
 	}
 }
 
@@ -34,43 +45,140 @@ func (m SysInfoModule) Id() string {
 	return "sysinfo"
 }
 
+func (m SysInfoModule) ConfigSection() string {
+	return "sysInfo"
+}
+
+func (m SysInfoModule) Addresses() []string {
+	return []string{}
+}
+
+func (m SysInfoModule) Placeholders() []chatbox.PlaceholderInfo {
+	placeholders := []chatbox.PlaceholderInfo{}
+	for _, collector := range m.container.config.Collectors {
+		placeholders = append(placeholders, m.collectorPlaceholders(collector)...)
+		placeholders = append(placeholders, chatbox.PlaceholderInfo{
+			Name:        "sysinfo.error." + collector,
+			Description: "Error of the " + collector + " collector, only set while reading it fails",
+			Example:     "failed to read temperatures: no sensors found",
+			UpdateRate:  m.interval(collector),
+		})
+	}
+
+	placeholders = append(placeholders, []chatbox.PlaceholderInfo{
+		{Name: "sysinfo.time.12h", Description: "Local time (12 hour clock)", Example: "03:04:05 PM", UpdateRate: time.Second},
+		{Name: "sysinfo.time.24h", Description: "Local time (24 hour clock)", Example: "15:04:05", UpdateRate: time.Second},
+		{Name: "sysinfo.date", Description: "Local date", Example: "10/19/2026", UpdateRate: 24 * time.Hour},
+	}...)
+
+	for _, zone := range m.container.config.TimeZones {
+		placeholders = append(placeholders, chatbox.PlaceholderInfo{
+			Name:        "sysinfo.time.tz." + zone,
+			Description: "Time in " + zone,
+			Example:     "15:04:05",
+			UpdateRate:  time.Second,
+		})
+	}
+
+	return placeholders
+}
+
 func (m SysInfoModule) Init(client *osc.Client, dispatcher *osc.StandardDispatcher) error {
+	for _, zone := range m.container.config.TimeZones {
+		location, err := time.LoadLocation(zone)
+		if err != nil {
+			return err
+		}
+		m.container.timeZones[zone] = location
+	}
+
+	options := sysinfo.Options{
+		DiskPaths:         m.container.config.DiskPaths,
+		NetworkInterfaces: m.container.config.NetworkInterfaces,
+		TemperatureSensor: m.container.config.TemperatureSensor,
+	}
+	for _, name := range m.container.config.Collectors {
+		collector, err := sysinfo.New(name, options)
+		if err != nil {
+			return err
+		}
+		m.container.samplers = append(m.container.samplers, newSysInfoSampler(name, collector, m.interval(name)))
+	}
+
+	for _, sampler := range m.container.samplers {
+		sampler.start()
+	}
+
 	return nil
 }
 
+// interval returns how often a collector is read.
+func (m SysInfoModule) interval(collector string) time.Duration {
+	if seconds, ok := m.container.config.IntervalSeconds[collector]; ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return sysinfo.DefaultInterval(collector)
+}
+
 func (m SysInfoModule) Tick(client *osc.Client, chatbox *chatbox.ChatBoxBuilder) error {
-	m.triggerMeasure()
+	m.publishSamples(chatbox)
 	time24h, time12h := m.getCurrentTime()
 
-	chatbox.Placeholder("sysinfo.cpu", fmt.Sprintf("%d%%", m.container.currentCpu))
-	chatbox.Placeholder("sysinfo.memory", fmt.Sprintf("%d%%", m.container.currentMemory))
 	chatbox.Placeholder("sysinfo.time.12h", time12h)
 	chatbox.Placeholder("sysinfo.time.24h", time24h)
+	chatbox.Placeholder("sysinfo.date", m.container.locale.FormatDate(time.Now()))
+
+	for zone, location := range m.container.timeZones {
+		chatbox.Placeholder("sysinfo.time.tz."+zone, m.container.locale.FormatTime24h(time.Now().In(location)))
+	}
 
 	return nil
 }
 
-func (m SysInfoModule) triggerMeasure() {
-	go func() {
-		percent, err := cpu.Percent(time.Second, false)
+// publishSamples sets the placeholders of the latest samples, a failing collector sets
+// sysinfo.error.<collector> without affecting the others.
+func (m SysInfoModule) publishSamples(chatbox *chatbox.ChatBoxBuilder) {
+	for _, sampler := range m.container.samplers {
+		metrics, err := sampler.latest()
+		for _, metric := range metrics {
+			chatbox.Placeholder("sysinfo."+metric.Name, m.format(metric))
+		}
 		if err != nil {
-			log.Printf("Failed to read cpu percentage: %v", err)
-			return
+			chatbox.Placeholder("sysinfo.error."+sampler.name, err.Error())
 		}
-		m.container.currentCpu = int(percent[0])
+	}
+}
 
-		vm, err := mem.VirtualMemory()
-		if err != nil {
-			log.Printf("Failed to read memory percentage: %v", err)
-			return
+func (m SysInfoModule) SetPaused(paused bool) {
+	for _, sampler := range m.container.samplers {
+		sampler.setPaused(paused)
+	}
+}
+
+func (m SysInfoModule) Status() []string {
+	status := []string{}
+	for _, sampler := range m.container.samplers {
+		if _, err := sampler.latest(); err != nil {
+			status = append(status, fmt.Sprintf("%s: %v", sampler.name, err))
 		}
-		m.container.currentMemory = int(vm.UsedPercent)
-	}()
+	}
+
+	if len(status) == 0 {
+		status = append(status, fmt.Sprintf("sampling %d collectors", len(m.container.samplers)))
+	}
+	return status
+}
+
+// Shutdown stops the background samplers.
+func (m SysInfoModule) Shutdown() {
+	for _, sampler := range m.container.samplers {
+		sampler.shutdown()
+	}
 }
 
 func (m SysInfoModule) getCurrentTime() (string, string) {
 	now := time.Now()
-	time24h := now.Format("15:04:05")
-	time12h := now.Format("03:04:05 PM")
+	time24h := m.container.locale.FormatTime24h(now)
+	time12h := m.container.locale.FormatTime12h(now)
 	return time24h, time12h
 }
@@ -0,0 +1,148 @@
+package modules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/Glowman554/OpenOSC/sysinfo"
+)
+
+// collectorPlaceholders describes the placeholders of a sysinfo collector.
+func (m SysInfoModule) collectorPlaceholders(collector string) []chatbox.PlaceholderInfo {
+	placeholder := func(name string, description string, example string) chatbox.PlaceholderInfo {
+		return chatbox.PlaceholderInfo{Name: "sysinfo." + name, Description: description, Example: example, UpdateRate: m.interval(collector)}
+	}
+
+	switch collector {
+	case sysinfo.CollectorCPU:
+		return []chatbox.PlaceholderInfo{placeholder("cpu", "CPU usage", "12%")}
+	case sysinfo.CollectorCores:
+		return []chatbox.PlaceholderInfo{
+			placeholder("cpu.core*", "Usage of a CPU core, * is the core index", "27%"),
+			placeholder("cpu.cores", "Number of CPU cores", "16"),
+		}
+	case sysinfo.CollectorTemperature:
+		return []chatbox.PlaceholderInfo{
+			placeholder("temperature", "CPU temperature", "54.0°C"),
+			placeholder("temperature.max", "Highest temperature of all sensors", "61.0°C"),
+		}
+	case sysinfo.CollectorMemory:
+		return []chatbox.PlaceholderInfo{
+			placeholder("memory", "Memory usage", "48%"),
+			placeholder("memory.used", "Used memory", "15.2 GiB"),
+			placeholder("memory.total", "Total memory", "31.3 GiB"),
+			placeholder("swap", "Swap usage, if there is swap", "3%"),
+		}
+	case sysinfo.CollectorNetwork:
+		return []chatbox.PlaceholderInfo{
+			placeholder("network.down", "Download rate", "1.4 MiB/s"),
+			placeholder("network.up", "Upload rate", "96.0 KiB/s"),
+		}
+	case sysinfo.CollectorDisk:
+		placeholders := []chatbox.PlaceholderInfo{}
+		for name, path := range m.container.config.DiskPaths {
+			placeholders = append(placeholders,
+				placeholder("disk."+name, "Usage of "+path, "63%"),
+				placeholder("disk."+name+".used", "Used space on "+path, "301.7 GiB"),
+				placeholder("disk."+name+".free", "Free space on "+path, "174.1 GiB"),
+				placeholder("disk."+name+".total", "Size of "+path, "475.8 GiB"),
+			)
+		}
+		return placeholders
+	case sysinfo.CollectorDiskIO:
+		return []chatbox.PlaceholderInfo{
+			placeholder("disk.read", "Disk read rate", "12.5 MiB/s"),
+			placeholder("disk.write", "Disk write rate", "3.1 MiB/s"),
+		}
+	case sysinfo.CollectorBattery:
+		return []chatbox.PlaceholderInfo{
+			placeholder("battery", "Battery level", "81%"),
+			placeholder("battery.status", "Translated battery status (Charging, Discharging, Full, ...)", "Discharging"),
+		}
+	case sysinfo.CollectorLoad:
+		return []chatbox.PlaceholderInfo{
+			placeholder("load.1", "Load average of the last minute", "1.2"),
+			placeholder("load.5", "Load average of the last 5 minutes", "0.9"),
+			placeholder("load.15", "Load average of the last 15 minutes", "0.7"),
+		}
+	case sysinfo.CollectorUptime:
+		return []chatbox.PlaceholderInfo{placeholder("uptime", "System uptime", "2d 04:17")}
+	case sysinfo.CollectorProcesses:
+		return []chatbox.PlaceholderInfo{placeholder("processes", "Number of running processes", "412")}
+	default:
+		return []chatbox.PlaceholderInfo{}
+	}
+}
+
+func (m SysInfoModule) format(metric sysinfo.Metric) string {
+	precision := m.container.config.Precision
+
+	switch metric.Unit {
+	case sysinfo.Percent:
+		return m.container.locale.Percent(int(math.Round(metric.Value)))
+	case sysinfo.Bytes:
+		return m.formatBytes(metric.Value, "")
+	case sysinfo.BytesPerSecond:
+		// disk rates stay in bytes, bits are only common for networks
+		if m.container.config.NetworkUnit == "bits" && strings.HasPrefix(metric.Name, "network.") {
+			return m.formatScaled(metric.Value*8, 1000, []string{"bit/s", "kbit/s", "Mbit/s", "Gbit/s"})
+		}
+		return m.formatBytes(metric.Value, "/s")
+	case sysinfo.Celsius:
+		if m.container.config.TemperatureUnit == "fahrenheit" {
+			return m.container.locale.FormatNumber(metric.Value*9/5+32, precision) + "°F"
+		}
+		return m.container.locale.FormatNumber(metric.Value, precision) + "°C"
+	case sysinfo.Seconds:
+		return m.formatUptime(metric.Value)
+	case sysinfo.Count:
+		return strconv.Itoa(int(metric.Value))
+	case sysinfo.Text:
+		return m.container.locale.T(metric.Text)
+	default:
+		return m.container.locale.FormatNumber(metric.Value, precision)
+	}
+}
+
+func (m SysInfoModule) formatBytes(value float64, suffix string) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	base := 1024.0
+	if m.container.config.ByteUnit == "si" {
+		units = []string{"B", "kB", "MB", "GB", "TB"}
+		base = 1000
+	}
+
+	for i := range units {
+		units[i] += suffix
+	}
+	return m.formatScaled(value, base, units)
+}
+
+func (m SysInfoModule) formatScaled(value float64, base float64, units []string) string {
+	unit := 0
+	for value >= base && unit < len(units)-1 {
+		value /= base
+		unit++
+	}
+
+	precision := m.container.config.Precision
+	if unit == 0 {
+		precision = 0
+	}
+	return m.container.locale.FormatNumber(value, precision) + " " + units[unit]
+}
+
+func (m SysInfoModule) formatUptime(seconds float64) string {
+	uptime := int(seconds)
+	days := uptime / 86400
+	hours := uptime / 3600 % 24
+	minutes := uptime / 60 % 60
+
+	if days > 0 {
+		return fmt.Sprintf("%dd %02d:%02d", days, hours, minutes)
+	}
+	return fmt.Sprintf("%02d:%02d", hours, minutes)
+}
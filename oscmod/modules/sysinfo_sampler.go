@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/sysinfo"
+)
+
+// sysInfoSampler reads one collector in the background at its own interval, Tick only publishes
+// the latest sample so slow collectors never hold up the chatbox.
+type sysInfoSampler struct {
+	name      string
+	collector sysinfo.Collector
+	interval  time.Duration
+
+	metrics []sysinfo.Metric
+	// sampled is the time of the last successful read, err the error of the latest read.
+	sampled time.Time
+	err     error
+	paused  bool
+	mutex   sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newSysInfoSampler(name string, collector sysinfo.Collector, interval time.Duration) *sysInfoSampler {
+	return &sysInfoSampler{
+		name:      name,
+		collector: collector,
+		interval:  interval,
+		metrics:   []sysinfo.Metric{},
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+func (s *sysInfoSampler) start() {
+	go s.run()
+}
+
+func (s *sysInfoSampler) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.sample()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mutex.Lock()
+			paused := s.paused
+			s.mutex.Unlock()
+
+			if !paused {
+				s.sample()
+			}
+		}
+	}
+}
+
+func (s *sysInfoSampler) sample() {
+	metrics, err := s.collector.Read()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// errors are only logged when they change, a missing sensor would spam the log otherwise
+	if err != nil && (s.err == nil || s.err.Error() != err.Error()) {
+		log.Printf("Failed to read %s: %v", s.name, err)
+	} else if err == nil && s.err != nil {
+		log.Printf("Reading %s works again", s.name)
+	}
+	s.err = err
+
+	// partial results (e.g. one of several disks failed) are still used
+	if len(metrics) > 0 || err == nil {
+		s.metrics = metrics
+		s.sampled = time.Now()
+	}
+}
+
+// latest returns the metrics of the last successful read, they are dropped once they are older
+// than sysInfoTTL or a few intervals so failing collectors do not show stale values forever.
+func (s *sysInfoSampler) latest() ([]sysinfo.Metric, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if time.Since(s.sampled) > max(sysInfoTTL, 3*s.interval) {
+		return []sysinfo.Metric{}, s.err
+	}
+	return s.metrics, s.err
+}
+
+func (s *sysInfoSampler) setPaused(paused bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.paused = paused
+}
+
+// shutdown stops the sampler and waits for a running read to finish.
+func (s *sysInfoSampler) shutdown() {
+	close(s.stop)
+	<-s.done
+}
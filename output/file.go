@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileOutput writes the text to a plain text file, e.g. for an OBS text source reading from a file.
+type FileOutput struct {
+	path    string
+	last    string
+	written bool
+}
+
+func NewFileOutput(path string) *FileOutput {
+	return &FileOutput{path: path}
+}
+
+func (o *FileOutput) Name() string {
+	return "file " + o.path
+}
+
+// Write replaces the file through a rename, so readers never see a partially written file.
+func (o *FileOutput) Write(text string) error {
+	if o.written && text == o.last {
+		return nil
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(o.path), ".openosc-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	err = temp.Chmod(0644)
+	if err == nil {
+		_, err = temp.WriteString(text)
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	err = os.Rename(temp.Name(), o.path)
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to replace %s: %w", o.path, err)
+	}
+
+	o.last = text
+	o.written = true
+	return nil
+}
+
+func (o *FileOutput) Close() error {
+	return nil
+}
@@ -0,0 +1,243 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// obs-websocket v5 opcodes
+const (
+	obsOpHello      = 0
+	obsOpIdentify   = 1
+	obsOpIdentified = 2
+	obsOpRequest    = 6
+)
+
+const obsReconnectDelay = 5 * time.Second
+
+type obsMessage struct {
+	Op   int            `json:"op"`
+	Data map[string]any `json:"d"`
+}
+
+type obsHello struct {
+	Op   int `json:"op"`
+	Data struct {
+		RPCVersion     int `json:"rpcVersion"`
+		Authentication *struct {
+			Challenge string `json:"challenge"`
+			Salt      string `json:"salt"`
+		} `json:"authentication"`
+	} `json:"d"`
+}
+
+// OBSOutput sets the text of an OBS text source through obs-websocket v5. The connection is made
+// in the background on the first write and retried while OBS is not running.
+type OBSOutput struct {
+	address  string
+	password string
+	source   string
+
+	pending chan string
+	done    chan struct{}
+	stopped chan struct{}
+	last    string
+	written bool
+	once    sync.Once
+}
+
+func NewOBSOutput(address string, password string, source string) *OBSOutput {
+	return &OBSOutput{
+		address:  address,
+		password: password,
+		source:   source,
+		pending:  make(chan string, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+func (o *OBSOutput) Name() string {
+	return "OBS source " + o.source
+}
+
+// Write queues the text, only the latest text is kept while OBS is busy or disconnected.
+func (o *OBSOutput) Write(text string) error {
+	// an empty text before anything was written does not need a connection
+	if text == o.last && (o.written || text == "") {
+		return nil
+	}
+	o.last = text
+	o.written = true
+
+	o.once.Do(func() {
+		go o.run()
+	})
+
+	select {
+	case <-o.pending:
+	default:
+	}
+	o.pending <- text
+	return nil
+}
+
+// Close stops the connection after sending the pending text, waiting at most a second for it.
+func (o *OBSOutput) Close() error {
+	started := true
+	o.once.Do(func() {
+		started = false
+	})
+
+	select {
+	case <-o.done:
+		return nil
+	default:
+		close(o.done)
+	}
+
+	if started {
+		select {
+		case <-o.stopped:
+		case <-time.After(time.Second):
+		}
+	}
+	return nil
+}
+
+func (o *OBSOutput) run() {
+	defer close(o.stopped)
+
+	text := ""
+	for {
+		conn, err := o.connect()
+		if err != nil {
+			log.Printf("Failed to connect to OBS at %s: %v", o.address, err)
+		} else {
+			text = o.serve(conn, text)
+			conn.Close()
+		}
+
+		select {
+		case <-o.done:
+			return
+		case <-time.After(obsReconnectDelay):
+		}
+	}
+}
+
+// serve sends the latest text until the connection fails or the output is closed, the last text
+// is returned so it can be restored after reconnecting.
+func (o *OBSOutput) serve(conn *websocket.Conn, text string) string {
+	// responses are not needed but have to be read to notice a closed connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if text != "" {
+		if err := o.setText(conn, text); err != nil {
+			log.Printf("Failed to update OBS source %s: %v", o.source, err)
+			return text
+		}
+	}
+
+	for {
+		select {
+		case text = <-o.pending:
+			if err := o.setText(conn, text); err != nil {
+				log.Printf("Failed to update OBS source %s: %v", o.source, err)
+				return text
+			}
+		case <-closed:
+			return text
+		case <-o.done:
+			select {
+			case text = <-o.pending:
+				o.setText(conn, text)
+			default:
+			}
+			return text
+		}
+	}
+}
+
+func (o *OBSOutput) connect() (*websocket.Conn, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 5 * time.Second}
+	conn, _, err := dialer.Dial(o.address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var hello obsHello
+	err = conn.ReadJSON(&hello)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read hello: %w", err)
+	}
+	if hello.Op != obsOpHello {
+		conn.Close()
+		return nil, fmt.Errorf("expected hello, got op %d", hello.Op)
+	}
+
+	identify := map[string]any{
+		"rpcVersion":         1,
+		"eventSubscriptions": 0,
+	}
+	if hello.Data.Authentication != nil {
+		identify["authentication"] = obsAuthentication(o.password, hello.Data.Authentication.Salt, hello.Data.Authentication.Challenge)
+	}
+
+	err = conn.WriteJSON(obsMessage{Op: obsOpIdentify, Data: identify})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to identify: %w", err)
+	}
+
+	var identified obsMessage
+	err = conn.ReadJSON(&identified)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to identify (wrong password?): %w", err)
+	}
+	if identified.Op != obsOpIdentified {
+		conn.Close()
+		return nil, fmt.Errorf("expected identified, got op %d", identified.Op)
+	}
+
+	log.Printf("Connected to OBS at %s", o.address)
+	return conn, nil
+}
+
+func (o *OBSOutput) setText(conn *websocket.Conn, text string) error {
+	return conn.WriteJSON(obsMessage{Op: obsOpRequest, Data: map[string]any{
+		"requestType": "SetInputSettings",
+		"requestId":   strconv.FormatInt(time.Now().UnixNano(), 36),
+		"requestData": map[string]any{
+			"inputName":     o.source,
+			"inputSettings": map[string]any{"text": text},
+		},
+	}})
+}
+
+// obsAuthentication computes base64(sha256(base64(sha256(password + salt)) + challenge)).
+func obsAuthentication(password string, salt string, challenge string) string {
+	secret := sha256.Sum256([]byte(password + salt))
+	auth := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(secret[:]) + challenge))
+	return base64.StdEncoding.EncodeToString(auth[:])
+}
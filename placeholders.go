@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func placeholdersCommand(args []string) error {
+	flags, configPath := newFlagSet("placeholders")
+	flags.Parse(args)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	registry := newPlaceholderRegistry(config, createModules(config))
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "NAME\tMODULE\tACTIVE\tUPDATE\tTTL\tEXAMPLE\tDESCRIPTION")
+	for _, placeholder := range registry.All() {
+		update := "every tick"
+		if placeholder.UpdateRate > 0 {
+			update = placeholder.UpdateRate.String()
+		}
+		ttl := "-"
+		if placeholder.TTL > 0 {
+			ttl = placeholder.TTL.String()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%t\t%s\t%s\t%s\t%s\n", placeholder.Name, placeholder.Module, placeholder.Active, update, ttl, placeholder.Example, placeholder.Description)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/control"
+	"github.com/Glowman554/OpenOSC/oscmod"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/Glowman554/OpenOSC/tui"
+	"github.com/hypebeast/go-osc/osc"
+	"github.com/mitchellh/go-ps"
+)
+
+func isVRChatRunning() bool {
+	processes, err := ps.Processes()
+	if err != nil {
+		return false
+	}
+
+	for _, p := range processes {
+		if strings.Contains(strings.ToLower(p.Executable()), "vrchat") {
+			return true
+		}
+	}
+	return false
+}
+
+func runCommand(args []string) error {
+	flags, configPath := newFlagSet("run")
+	enableTUI := flags.Bool("tui", false, "Show the interactive terminal UI (overrides tui from the config)")
+	flags.Parse(args)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	chatbox, err := newChatBox(config)
+	if err != nil {
+		return err
+	}
+
+	// for true {
+	// 	log.Println("Waiting for VRChat")
+
+	// 	if isVRChatRunning() {
+	// 		break
+	// 	}
+
+	// 	time.Sleep(2 * time.Second)
+	// }
+
+	modules := createModules(config)
+	manager := oscmod.NewModuleManager()
+
+	log.Println("Starting...")
+
+	client := osc.NewClient(config.SendIP, config.SendPort)
+
+	dispatcher := osc.NewStandardDispatcher()
+	server := &osc.Server{Addr: fmt.Sprintf("0.0.0.0:%d", config.ReceivePort), Dispatcher: dispatcher}
+	err = manager.RegisterDefaultHandler(dispatcher)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		if isModuleActive(config, module) {
+			err := module.Init(client, dispatcher)
+			if err != nil {
+				log.Fatalf("Failed to initialize module: %s (%v)", module.Name(), err)
+			} else {
+				log.Printf("Initialized %s", module.Name())
+				manager.Add(module)
+			}
+		}
+	}
+
+	if config.DBusControl {
+		err := control.NewService(manager, chatbox).Start()
+		if err != nil {
+			log.Fatalf("Failed to start D-Bus control: %v", err)
+		}
+		log.Printf("Published %s on the session bus", control.ServiceName)
+	}
+
+	// the dispatcher is not safe for concurrent use, so handlers are added before it is served
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil {
+			log.Fatalf("Failed to listen: %v", err)
+			panic(err)
+		}
+	}()
+
+	go runLoop(client, manager, chatbox)
+	if config.ChatboxConfig.UpdateIntervalMS > 0 {
+		go flushLoop(client, chatbox, time.Duration(config.ChatboxConfig.UpdateIntervalMS)*time.Millisecond)
+	}
+
+	if *enableTUI || config.TUI {
+		err = tui.NewTUI(manager, chatbox).Run()
+	} else {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+	}
+
+	log.Println("Shutting down...")
+	manager.Shutdown()
+	chatbox.Shutdown(client)
+
+	return err
+}
+
+func runLoop(client *osc.Client, manager *oscmod.ModuleManager, chatbox *chatbox.ChatBoxBuilder) {
+	for true {
+		chatbox.BeginTick()
+		manager.Tick(client, chatbox)
+		chatbox.EndTick(client)
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// flushLoop re-renders the chatbox between module ticks to animate marquees and progress bars.
+func flushLoop(client *osc.Client, chatbox *chatbox.ChatBoxBuilder, interval time.Duration) {
+	for true {
+		time.Sleep(interval)
+		chatbox.Flush(client)
+	}
+}
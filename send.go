@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hypebeast/go-osc/osc"
+)
+
+func sendCommand(args []string) error {
+	flags, configPath := newFlagSet("send")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: send [-config file] <address> [args...]")
+		fmt.Fprintln(flags.Output(), "Arguments are sent as bool, int, float or string depending on their format.")
+		fmt.Fprintln(flags.Output(), "Use a b:, i:, f: or s: prefix to force a type (e.g. s:true).")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return fmt.Errorf("missing address")
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	msg := osc.NewMessage(flags.Arg(0))
+	for _, arg := range flags.Args()[1:] {
+		value, err := parseOSCArgument(arg)
+		if err != nil {
+			return err
+		}
+		msg.Append(value)
+	}
+
+	client := osc.NewClient(config.SendIP, config.SendPort)
+	err = client.Send(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	fmt.Printf("Sent %s to %s:%d\n", msg, config.SendIP, config.SendPort)
+	return nil
+}
+
+func parseOSCArgument(arg string) (any, error) {
+	if prefix, value, ok := strings.Cut(arg, ":"); ok && len(prefix) == 1 {
+		switch prefix {
+		case "b":
+			return strconv.ParseBool(value)
+		case "i":
+			i, err := strconv.ParseInt(value, 10, 32)
+			return int32(i), err
+		case "f":
+			f, err := strconv.ParseFloat(value, 32)
+			return float32(f), err
+		case "s":
+			return value, nil
+		}
+	}
+
+	if arg == "true" || arg == "false" {
+		return arg == "true", nil
+	}
+
+	if i, err := strconv.ParseInt(arg, 10, 32); err == nil {
+		return int32(i), nil
+	}
+
+	if f, err := strconv.ParseFloat(arg, 32); err == nil {
+		return float32(f), nil
+	}
+
+	return arg, nil
+}
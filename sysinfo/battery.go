@@ -0,0 +1,105 @@
+package sysinfo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// upowerStates are the UPower device states, indexed by their value.
+var upowerStates = []string{"Unknown", "Charging", "Discharging", "Empty", "Full", "Not charging", "Discharging"}
+
+// BatteryCollector reads the first battery from sysfs and falls back to UPower, which also
+// covers systems without the sysfs power supply class.
+type BatteryCollector struct {
+	root string
+}
+
+// NewBatteryCollector reads batteries below root, usually /sys.
+func NewBatteryCollector(root string) *BatteryCollector {
+	return &BatteryCollector{
+		root: root,
+	}
+}
+
+func (c *BatteryCollector) Read() ([]Metric, error) {
+	metrics, err := c.readSysfs()
+	if err == nil {
+		return metrics, nil
+	}
+
+	metrics, upowerErr := c.readUPower()
+	if upowerErr != nil {
+		return nil, fmt.Errorf("failed to read battery: %w, upower: %w", err, upowerErr)
+	}
+	return metrics, nil
+}
+
+func (c *BatteryCollector) readSysfs() ([]Metric, error) {
+	batteries, err := filepath.Glob(filepath.Join(c.root, "class", "power_supply", "BAT*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(batteries) == 0 {
+		return nil, fmt.Errorf("no battery in %s", c.root)
+	}
+
+	capacity, err := os.ReadFile(filepath.Join(batteries[0], "capacity"))
+	if err != nil {
+		return nil, err
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSpace(string(capacity)), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := []Metric{{Name: "battery", Value: percent, Unit: Percent}}
+
+	status, err := os.ReadFile(filepath.Join(batteries[0], "status"))
+	if err == nil {
+		metrics = append(metrics, Metric{Name: "battery.status", Text: strings.TrimSpace(string(status)), Unit: Text})
+	}
+
+	return metrics, nil
+}
+
+func (c *BatteryCollector) readUPower() ([]Metric, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, err
+	}
+
+	device := conn.Object("org.freedesktop.UPower", "/org/freedesktop/UPower/devices/DisplayDevice")
+
+	present, err := device.GetProperty("org.freedesktop.UPower.Device.IsPresent")
+	if err != nil {
+		return nil, err
+	}
+	if isPresent, ok := present.Value().(bool); !ok || !isPresent {
+		return nil, fmt.Errorf("no battery present")
+	}
+
+	percentage, err := device.GetProperty("org.freedesktop.UPower.Device.Percentage")
+	if err != nil {
+		return nil, err
+	}
+	percent, ok := percentage.Value().(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid type for Percentage: %T", percentage.Value())
+	}
+
+	metrics := []Metric{{Name: "battery", Value: percent, Unit: Percent}}
+
+	state, err := device.GetProperty("org.freedesktop.UPower.Device.State")
+	if err == nil {
+		if value, ok := state.Value().(uint32); ok && int(value) < len(upowerStates) {
+			metrics = append(metrics, Metric{Name: "battery.status", Text: upowerStates[value], Unit: Text})
+		}
+	}
+
+	return metrics, nil
+}
@@ -0,0 +1,92 @@
+package sysinfo
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	CollectorCPU         = "cpu"
+	CollectorCores       = "cores"
+	CollectorTemperature = "temperature"
+	CollectorMemory      = "memory"
+	CollectorNetwork     = "network"
+	CollectorDisk        = "disk"
+	CollectorDiskIO      = "diskio"
+	CollectorBattery     = "battery"
+	CollectorLoad        = "load"
+	CollectorUptime      = "uptime"
+	CollectorProcesses   = "processes"
+)
+
+var Collectors = []string{
+	CollectorCPU,
+	CollectorCores,
+	CollectorTemperature,
+	CollectorMemory,
+	CollectorNetwork,
+	CollectorDisk,
+	CollectorDiskIO,
+	CollectorBattery,
+	CollectorLoad,
+	CollectorUptime,
+	CollectorProcesses,
+}
+
+func IsCollector(name string) bool {
+	return slices.Contains(Collectors, name)
+}
+
+// DefaultInterval is how often a collector is read unless configured otherwise, values that
+// barely change are read less often.
+func DefaultInterval(name string) time.Duration {
+	switch name {
+	case CollectorCPU, CollectorCores, CollectorNetwork, CollectorDiskIO:
+		return 2 * time.Second
+	case CollectorTemperature, CollectorMemory, CollectorLoad, CollectorProcesses:
+		return 5 * time.Second
+	case CollectorDisk, CollectorBattery, CollectorUptime:
+		return 30 * time.Second
+	default:
+		return 5 * time.Second
+	}
+}
+
+type Options struct {
+	// DiskPaths maps names to mount points, e.g. "root" to "/" for sysinfo.disk.root.
+	DiskPaths map[string]string
+	// NetworkInterfaces are summed up, all but loopback interfaces are used if empty.
+	NetworkInterfaces []string
+	// TemperatureSensor is part of the sensor key to use for the CPU temperature, e.g. "tctl".
+	TemperatureSensor string
+}
+
+func New(name string, options Options) (Collector, error) {
+	switch name {
+	case CollectorCPU:
+		return NewCPUCollector(), nil
+	case CollectorCores:
+		return NewCoresCollector(), nil
+	case CollectorTemperature:
+		return NewTemperatureCollector(options.TemperatureSensor), nil
+	case CollectorMemory:
+		return NewMemoryCollector(), nil
+	case CollectorNetwork:
+		return NewNetworkCollector(options.NetworkInterfaces), nil
+	case CollectorDisk:
+		return NewDiskCollector(options.DiskPaths), nil
+	case CollectorDiskIO:
+		return NewDiskIOCollector(), nil
+	case CollectorBattery:
+		return NewBatteryCollector("/sys"), nil
+	case CollectorLoad:
+		return NewLoadCollector(), nil
+	case CollectorUptime:
+		return NewUptimeCollector(), nil
+	case CollectorProcesses:
+		return NewProcessesCollector(), nil
+	default:
+		return nil, fmt.Errorf("unknown collector %s", name)
+	}
+}
@@ -0,0 +1,105 @@
+package sysinfo
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// cpuUsage turns cumulative cpu times into a percentage of the time since the previous sample,
+// so reading it never blocks.
+type cpuUsage struct {
+	previous []cpu.TimesStat
+	mutex    sync.Mutex
+}
+
+// update returns nil on the first sample or if the number of cpus changed.
+func (u *cpuUsage) update(times []cpu.TimesStat) []float64 {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	previous := u.previous
+	u.previous = times
+	if len(previous) != len(times) {
+		return nil
+	}
+
+	percent := make([]float64, len(times))
+	for i := range times {
+		percent[i] = busyPercent(previous[i], times[i])
+	}
+	return percent
+}
+
+func busyPercent(before cpu.TimesStat, after cpu.TimesStat) float64 {
+	beforeTotal, beforeBusy := busyTimes(before)
+	afterTotal, afterBusy := busyTimes(after)
+
+	if afterBusy <= beforeBusy {
+		return 0
+	}
+	if afterTotal <= beforeTotal {
+		return 100
+	}
+	return min(100, (afterBusy-beforeBusy)/(afterTotal-beforeTotal)*100)
+}
+
+func busyTimes(times cpu.TimesStat) (float64, float64) {
+	total := times.Total()
+	if runtime.GOOS == "linux" {
+		// guest time is already part of user time on linux
+		total -= times.Guest + times.GuestNice
+	}
+	return total, total - times.Idle - times.Iowait
+}
+
+type CPUCollector struct {
+	usage cpuUsage
+}
+
+func NewCPUCollector() *CPUCollector {
+	return &CPUCollector{}
+}
+
+// Read reports the usage since the previous Read, the first Read returns no metrics.
+func (c *CPUCollector) Read() ([]Metric, error) {
+	times, err := cpu.Times(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpu times: %w", err)
+	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("failed to read cpu times: no cpu reported")
+	}
+
+	percent := c.usage.update(times)
+	if percent == nil {
+		return []Metric{}, nil
+	}
+
+	return []Metric{{Name: "cpu", Value: percent[0], Unit: Percent}}, nil
+}
+
+type CoresCollector struct {
+	usage cpuUsage
+}
+
+func NewCoresCollector() *CoresCollector {
+	return &CoresCollector{}
+}
+
+// Read reports the usage since the previous Read, the first Read only reports the core count.
+func (c *CoresCollector) Read() ([]Metric, error) {
+	times, err := cpu.Times(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read per core times: %w", err)
+	}
+
+	metrics := []Metric{}
+	for core, value := range c.usage.update(times) {
+		metrics = append(metrics, Metric{Name: fmt.Sprintf("cpu.core%d", core), Value: value, Unit: Percent})
+	}
+	metrics = append(metrics, Metric{Name: "cpu.cores", Value: float64(len(times)), Unit: Count})
+	return metrics, nil
+}
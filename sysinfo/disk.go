@@ -0,0 +1,93 @@
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+type DiskCollector struct {
+	paths map[string]string
+}
+
+func NewDiskCollector(paths map[string]string) *DiskCollector {
+	return &DiskCollector{
+		paths: paths,
+	}
+}
+
+func (c *DiskCollector) Read() ([]Metric, error) {
+	metrics := []Metric{}
+	errs := []error{}
+
+	for name, path := range c.paths {
+		usage, err := disk.Usage(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read disk usage of %s: %w", path, err))
+			continue
+		}
+
+		prefix := "disk." + name
+		metrics = append(metrics,
+			Metric{Name: prefix, Value: usage.UsedPercent, Unit: Percent},
+			Metric{Name: prefix + ".used", Value: float64(usage.Used), Unit: Bytes},
+			Metric{Name: prefix + ".free", Value: float64(usage.Free), Unit: Bytes},
+			Metric{Name: prefix + ".total", Value: float64(usage.Total), Unit: Bytes},
+		)
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+type DiskIOCollector struct {
+	rate counterRate
+}
+
+func NewDiskIOCollector() *DiskIOCollector {
+	return &DiskIOCollector{}
+}
+
+func (c *DiskIOCollector) Read() ([]Metric, error) {
+	counters, err := disk.IOCounters()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disk counters: %w", err)
+	}
+
+	var read, written uint64
+	for name, counter := range counters {
+		if !c.include(name) {
+			continue
+		}
+		read += counter.ReadBytes
+		written += counter.WriteBytes
+	}
+
+	rates, ok := c.rate.update(map[string]uint64{"read": read, "write": written}, time.Now())
+	if !ok {
+		return []Metric{}, nil
+	}
+
+	return []Metric{
+		{Name: "disk.read", Value: rates["read"], Unit: BytesPerSecond},
+		{Name: "disk.write", Value: rates["write"], Unit: BytesPerSecond},
+	}, nil
+}
+
+// include skips partitions and virtual devices on Linux, their I/O is already counted for the disk.
+func (c *DiskIOCollector) include(name string) bool {
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") || strings.HasPrefix(name, "zram") {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join("/sys/block", name))
+	return err == nil
+}
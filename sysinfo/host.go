@@ -0,0 +1,61 @@
+package sysinfo
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+type LoadCollector struct {
+}
+
+func NewLoadCollector() *LoadCollector {
+	return &LoadCollector{}
+}
+
+func (c *LoadCollector) Read() ([]Metric, error) {
+	avg, err := load.Avg()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read load average: %w", err)
+	}
+
+	return []Metric{
+		{Name: "load.1", Value: avg.Load1, Unit: Number},
+		{Name: "load.5", Value: avg.Load5, Unit: Number},
+		{Name: "load.15", Value: avg.Load15, Unit: Number},
+	}, nil
+}
+
+type UptimeCollector struct {
+}
+
+func NewUptimeCollector() *UptimeCollector {
+	return &UptimeCollector{}
+}
+
+func (c *UptimeCollector) Read() ([]Metric, error) {
+	uptime, err := host.Uptime()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uptime: %w", err)
+	}
+
+	return []Metric{{Name: "uptime", Value: float64(uptime), Unit: Seconds}}, nil
+}
+
+type ProcessesCollector struct {
+}
+
+func NewProcessesCollector() *ProcessesCollector {
+	return &ProcessesCollector{}
+}
+
+func (c *ProcessesCollector) Read() ([]Metric, error) {
+	pids, err := process.Pids()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read processes: %w", err)
+	}
+
+	return []Metric{{Name: "processes", Value: float64(len(pids)), Unit: Count}}, nil
+}
@@ -0,0 +1,34 @@
+package sysinfo
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+type MemoryCollector struct {
+}
+
+func NewMemoryCollector() *MemoryCollector {
+	return &MemoryCollector{}
+}
+
+func (c *MemoryCollector) Read() ([]Metric, error) {
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read memory percentage: %w", err)
+	}
+
+	metrics := []Metric{
+		{Name: "memory", Value: vm.UsedPercent, Unit: Percent},
+		{Name: "memory.used", Value: float64(vm.Used), Unit: Bytes},
+		{Name: "memory.total", Value: float64(vm.Total), Unit: Bytes},
+	}
+
+	swap, err := mem.SwapMemory()
+	if err == nil && swap.Total > 0 {
+		metrics = append(metrics, Metric{Name: "swap", Value: swap.UsedPercent, Unit: Percent})
+	}
+
+	return metrics, nil
+}
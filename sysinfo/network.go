@@ -0,0 +1,53 @@
+package sysinfo
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+type NetworkCollector struct {
+	interfaces []string
+	rate       counterRate
+}
+
+func NewNetworkCollector(interfaces []string) *NetworkCollector {
+	return &NetworkCollector{
+		interfaces: interfaces,
+	}
+}
+
+func (c *NetworkCollector) Read() ([]Metric, error) {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network counters: %w", err)
+	}
+
+	var received, sent uint64
+	for _, counter := range counters {
+		if !c.include(counter.Name) {
+			continue
+		}
+		received += counter.BytesRecv
+		sent += counter.BytesSent
+	}
+
+	rates, ok := c.rate.update(map[string]uint64{"down": received, "up": sent}, time.Now())
+	if !ok {
+		return []Metric{}, nil
+	}
+
+	return []Metric{
+		{Name: "network.down", Value: rates["down"], Unit: BytesPerSecond},
+		{Name: "network.up", Value: rates["up"], Unit: BytesPerSecond},
+	}, nil
+}
+
+func (c *NetworkCollector) include(name string) bool {
+	if len(c.interfaces) > 0 {
+		return slices.Contains(c.interfaces, name)
+	}
+	return name != "lo" && name != "lo0"
+}
@@ -0,0 +1,135 @@
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/go-ps"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessSelf matches the OpenOSC process itself.
+const ProcessSelf = "self"
+
+// linux truncates process names to 15 characters.
+const processNameLength = 15
+
+// ProcessCollector reports the resource usage of processes found by executable name, processes
+// with the same name (e.g. wine helpers) are summed up. For every name it emits <name>.cpu,
+// <name>.memory and <name>.threads while the process is running.
+type ProcessCollector struct {
+	// executables maps metric names to executable names.
+	executables map[string]string
+	// processes are kept between reads so the cpu usage is the delta since the previous read.
+	processes map[int32]*process.Process
+	mutex     sync.Mutex
+}
+
+func NewProcessCollector(executables map[string]string) *ProcessCollector {
+	return &ProcessCollector{
+		executables: executables,
+		processes:   map[int32]*process.Process{},
+	}
+}
+
+func (c *ProcessCollector) Read() ([]Metric, error) {
+	running, err := ps.Processes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	seen := map[int32]bool{}
+	metrics := []Metric{}
+	errs := []error{}
+	for name, executable := range c.executables {
+		pids := matchProcesses(running, executable)
+		if len(pids) == 0 {
+			continue
+		}
+
+		cpu, memory, threads := 0.0, 0.0, 0.0
+		for _, pid := range pids {
+			seen[pid] = true
+			p, err := c.process(pid)
+			if err != nil {
+				// the process exited since it was listed
+				continue
+			}
+
+			percent, err := p.Percent(0)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read cpu usage of %s: %w", executable, err))
+				continue
+			}
+			info, err := p.MemoryInfo()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read memory of %s: %w", executable, err))
+				continue
+			}
+			count, err := p.NumThreads()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read threads of %s: %w", executable, err))
+				continue
+			}
+
+			// Percent is relative to one core, the placeholders show the share of the machine
+			cpu += percent / float64(runtime.NumCPU())
+			memory += float64(info.RSS)
+			threads += float64(count)
+		}
+
+		metrics = append(metrics,
+			Metric{Name: name + ".cpu", Value: cpu, Unit: Percent},
+			Metric{Name: name + ".memory", Value: memory, Unit: Bytes},
+			Metric{Name: name + ".threads", Value: threads, Unit: Count},
+		)
+	}
+
+	for pid := range c.processes {
+		if !seen[pid] {
+			delete(c.processes, pid)
+		}
+	}
+
+	return metrics, errors.Join(errs...)
+}
+
+func (c *ProcessCollector) process(pid int32) (*process.Process, error) {
+	if p, ok := c.processes[pid]; ok {
+		return p, nil
+	}
+
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	c.processes[pid] = p
+	return p, nil
+}
+
+func matchProcesses(running []ps.Process, executable string) []int32 {
+	if executable == ProcessSelf {
+		return []int32{int32(os.Getpid())}
+	}
+
+	truncated := executable
+	if len(truncated) > processNameLength {
+		truncated = truncated[:processNameLength]
+	}
+
+	pids := []int32{}
+	for _, p := range running {
+		name := p.Executable()
+		if strings.EqualFold(name, executable) || strings.EqualFold(name, truncated) {
+			pids = append(pids, int32(p.Pid()))
+		}
+	}
+	return pids
+}
@@ -0,0 +1,29 @@
+package sysinfo
+
+import "time"
+
+// counterRate turns ever increasing counters into per second rates between two reads.
+type counterRate struct {
+	last map[string]uint64
+	at   time.Time
+}
+
+// update returns the rates since the previous update, ok is false on the first one.
+func (r *counterRate) update(counters map[string]uint64, now time.Time) (map[string]float64, bool) {
+	previous, previousAt := r.last, r.at
+	r.last, r.at = counters, now
+
+	elapsed := now.Sub(previousAt).Seconds()
+	if previous == nil || elapsed <= 0 {
+		return nil, false
+	}
+
+	rates := map[string]float64{}
+	for name, value := range counters {
+		// counters reset when interfaces come back or wrap around
+		if value >= previous[name] {
+			rates[name] = float64(value-previous[name]) / elapsed
+		}
+	}
+	return rates, true
+}
@@ -0,0 +1,66 @@
+package sysinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// cpuSensors are checked in order if no sensor is configured: Intel package, AMD Tctl/Tdie, ARM
+// boards and finally the ACPI thermal zone.
+var cpuSensors = []string{"package_id", "tctl", "tdie", "coretemp", "k10temp", "cpu", "acpitz"}
+
+type TemperatureCollector struct {
+	sensor string
+}
+
+func NewTemperatureCollector(sensor string) *TemperatureCollector {
+	return &TemperatureCollector{
+		sensor: strings.ToLower(sensor),
+	}
+}
+
+func (c *TemperatureCollector) Read() ([]Metric, error) {
+	// some sensors failing is reported as warnings next to the working ones
+	sensors, err := host.SensorsTemperatures()
+	if len(sensors) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no sensors found")
+		}
+		return nil, fmt.Errorf("failed to read temperatures: %w", err)
+	}
+
+	candidates := cpuSensors
+	if c.sensor != "" {
+		candidates = []string{c.sensor}
+	}
+
+	metrics := []Metric{}
+	for _, candidate := range candidates {
+		if sensor, ok := c.find(sensors, candidate); ok {
+			metrics = append(metrics, Metric{Name: "temperature", Value: sensor.Temperature, Unit: Celsius})
+			break
+		}
+	}
+	if len(metrics) == 0 && c.sensor != "" {
+		return nil, fmt.Errorf("temperature sensor %s not found", c.sensor)
+	}
+
+	highest := sensors[0].Temperature
+	for _, sensor := range sensors {
+		highest = max(highest, sensor.Temperature)
+	}
+	metrics = append(metrics, Metric{Name: "temperature.max", Value: highest, Unit: Celsius})
+
+	return metrics, nil
+}
+
+func (c *TemperatureCollector) find(sensors []host.TemperatureStat, candidate string) (host.TemperatureStat, bool) {
+	for _, sensor := range sensors {
+		if strings.Contains(strings.ToLower(sensor.SensorKey), candidate) && sensor.Temperature > 0 {
+			return sensor, true
+		}
+	}
+	return host.TemperatureStat{}, false
+}
@@ -0,0 +1,28 @@
+package sysinfo
+
+// Unit decides how a metric is formatted.
+type Unit int
+
+const (
+	Percent Unit = iota
+	Bytes
+	BytesPerSecond
+	Celsius
+	Seconds
+	Count
+	Number
+	// Text metrics use Metric.Text instead of Value.
+	Text
+)
+
+// Metric is one reading, Name is the placeholder name without the sysinfo. prefix.
+type Metric struct {
+	Name  string
+	Value float64
+	Text  string
+	Unit  Unit
+}
+
+type Collector interface {
+	Read() ([]Metric, error)
+}
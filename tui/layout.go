@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fit truncates or pads s to exactly width runes.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	length := utf8.RuneCountInString(s)
+	if length > width {
+		runes := []rune(s)
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-length)
+}
+
+// box draws lines into a bordered pane of the given outer size.
+func box(title string, lines []string, width int, height int) []string {
+	if width < 4 || height < 2 {
+		return []string{}
+	}
+
+	inner := width - 2
+	header := "┌ " + title + " "
+	out := []string{fit(header+strings.Repeat("─", max(inner-utf8.RuneCountInString(header)+1, 0)), width-1) + "┐"}
+
+	for i := 0; i < height-2; i++ {
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+		}
+		out = append(out, "│"+fit(line, inner)+"│")
+	}
+
+	out = append(out, "└"+strings.Repeat("─", inner)+"┘")
+	return out
+}
+
+// sideBySide joins two panes of equal height horizontally.
+func sideBySide(left []string, right []string) []string {
+	out := []string{}
+	for i := 0; i < max(len(left), len(right)); i++ {
+		line := ""
+		if i < len(left) {
+			line += left[i]
+		}
+		if i < len(right) {
+			line += right[i]
+		}
+		out = append(out, line)
+	}
+	return out
+}
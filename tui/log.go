@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+)
+
+// LogBuffer keeps the last lines written to it, it is used as log output while the TUI is active.
+type LogBuffer struct {
+	lines   []string
+	partial string
+	size    int
+	mutex   sync.Mutex
+}
+
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{
+		lines: []string{},
+		size:  size,
+	}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	text := b.partial + string(p)
+	lines := strings.Split(text, "\n")
+	b.partial = lines[len(lines)-1]
+
+	b.lines = append(b.lines, lines[:len(lines)-1]...)
+	if len(b.lines) > b.size {
+		b.lines = b.lines[len(b.lines)-b.size:]
+	}
+
+	return len(p), nil
+}
+
+// Last returns up to n of the most recent lines.
+func (b *LogBuffer) Last(n int) []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if n > len(b.lines) {
+		n = len(b.lines)
+	}
+	return append([]string{}, b.lines[len(b.lines)-n:]...)
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Glowman554/OpenOSC/oscmod"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"golang.org/x/term"
+)
+
+type TUI struct {
+	manager *oscmod.ModuleManager
+	chatbox *chatbox.ChatBoxBuilder
+	logs    *LogBuffer
+}
+
+func NewTUI(manager *oscmod.ModuleManager, chatbox *chatbox.ChatBoxBuilder) *TUI {
+	return &TUI{
+		manager: manager,
+		chatbox: chatbox,
+		logs:    NewLogBuffer(500),
+	}
+}
+
+// Run takes over the terminal until the user quits.
+func (t *TUI) Run() error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("stdin is not a terminal")
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	log.SetOutput(t.logs)
+	defer log.SetOutput(os.Stderr)
+
+	fmt.Print("\033[?1049h\033[?25l")
+	defer fmt.Print("\033[?25h\033[?1049l")
+
+	keys := make(chan byte)
+	go func() {
+		buffer := make([]byte, 1)
+		for {
+			_, err := os.Stdin.Read(buffer)
+			if err != nil {
+				close(keys)
+				return
+			}
+			keys <- buffer[0]
+		}
+	}()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		t.draw()
+
+		select {
+		case key, ok := <-keys:
+			if !ok || !t.handleKey(key) {
+				return nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleKey returns false when the TUI should exit.
+func (t *TUI) handleKey(key byte) bool {
+	switch key {
+	case 'q', 3: // 3 = ctrl-c
+		return false
+	case 'p':
+		t.chatbox.SetPaused(!t.chatbox.Paused())
+	case 'e', ' ':
+		oscmod.EmergencyStop(!oscmod.IsEmergencyStopped())
+	default:
+		if key >= '1' && key <= '9' {
+			statuses := t.manager.Statuses()
+			index := int(key - '1')
+			if index < len(statuses) {
+				t.manager.Toggle(statuses[index].Id)
+			}
+		}
+	}
+	return true
+}
+
+func (t *TUI) draw() {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	statuses := t.manager.Statuses()
+
+	chatboxTitle := "Chatbox"
+	if t.chatbox.Paused() {
+		chatboxTitle += " [PAUSED]"
+	}
+	chatboxLines := strings.Split(strings.TrimRight(t.chatbox.Rendered(), "\n"), "\n")
+
+	moduleLines := []string{}
+	for i, status := range statuses {
+		enabled := " "
+		if status.Enabled {
+			enabled = "x"
+		}
+		line := fmt.Sprintf("%d [%s] %s", i+1, enabled, status.Name)
+		if status.LastError != nil {
+			line += " (" + status.LastError.Error() + ")"
+		}
+		moduleLines = append(moduleLines, line)
+	}
+
+	leashLines := []string{"not active"}
+	openShockLines := []string{"not active"}
+	for _, status := range statuses {
+		switch status.Id {
+		case "leash":
+			leashLines = status.Status
+		case "openshock":
+			openShockLines = status.Status
+		}
+	}
+
+	leftWidth := width / 2
+	rightWidth := width - leftWidth
+	topHeight := 11
+	middleHeight := 7
+	logHeight := max(height-topHeight-middleHeight-1, 3)
+
+	out := sideBySide(box(chatboxTitle, chatboxLines, leftWidth, topHeight), box("Modules", moduleLines, rightWidth, topHeight))
+	out = append(out, sideBySide(box("Leash", leashLines, leftWidth, middleHeight), box("OpenShock", openShockLines, rightWidth, middleHeight))...)
+	out = append(out, box("Log", t.logs.Last(logHeight-2), width, logHeight)...)
+
+	footer := "[1-9] toggle module  [p] pause chatbox  [e] emergency stop  [q] quit"
+	if oscmod.IsEmergencyStopped() {
+		footer = "\033[1;31mEMERGENCY STOP\033[0m  " + footer
+	}
+	out = append(out, footer)
+
+	fmt.Print("\033[H" + strings.Join(out, "\033[K\r\n") + "\033[K\033[J")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Glowman554/OpenOSC/config"
+	"github.com/Glowman554/OpenOSC/locale"
+	"github.com/Glowman554/OpenOSC/mpris"
+	"github.com/Glowman554/OpenOSC/oscmod"
+	"github.com/Glowman554/OpenOSC/oscmod/chatbox"
+	"github.com/Glowman554/OpenOSC/sysinfo"
+)
+
+// checkConfig runs every check on a config, run refuses to start and check-config fails if it
+// returns an error.
+func checkConfig(cfg *config.Config) error {
+	errs := []error{cfg.Validate(), validateConfig(cfg)}
+
+	modules := createModules(cfg)
+
+	builder, err := newChatBox(cfg)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		// unknown or misspelled placeholders would otherwise only show up as empty text
+		err = newPlaceholderRegistry(cfg, modules).Validate(builder.ReferencedPlaceholders())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, id := range cfg.ActiveModules {
+		if !slices.ContainsFunc(modules, func(module oscmod.OSCModule) bool { return module.Id() == id }) {
+			errs = append(errs, fmt.Errorf("unknown module %s in activeModules", id))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateConfig checks the config values that name things defined by the feature packages,
+// config.Validate only checks the plain values.
+func validateConfig(cfg *config.Config) error {
+	errs := []error{}
+
+	if !chatbox.IsProgressStyle(cfg.Media.ProgressBarStyle) {
+		errs = append(errs, fmt.Errorf("media.progressBarStyle %q is not supported (available: %s)", cfg.Media.ProgressBarStyle, strings.Join(chatbox.ProgressStyles, ", ")))
+	}
+
+	if !mpris.IsPolicy(cfg.Media.PlayerPolicy) {
+		errs = append(errs, fmt.Errorf("media.playerPolicy %q is not supported (available: %s)", cfg.Media.PlayerPolicy, strings.Join(mpris.Policies, ", ")))
+	}
+
+	if cfg.Notifications.Message != "" {
+		if _, err := chatbox.ParseTemplate(cfg.Notifications.Message); err != nil {
+			errs = append(errs, fmt.Errorf("notifications.message: %w", err))
+		}
+	}
+
+	if !locale.Exists(cfg.Locale.Language) {
+		errs = append(errs, fmt.Errorf("locale.language %q is not supported (available: %s)", cfg.Locale.Language, strings.Join(locale.Languages(), ", ")))
+	}
+
+	for _, collector := range cfg.SysInfo.Collectors {
+		if !sysinfo.IsCollector(collector) {
+			errs = append(errs, fmt.Errorf("sysInfo.collectors: %q is not supported (available: %s)", collector, strings.Join(sysinfo.Collectors, ", ")))
+		}
+	}
+
+	for collector := range cfg.SysInfo.IntervalSeconds {
+		if !sysinfo.IsCollector(collector) {
+			errs = append(errs, fmt.Errorf("sysInfo.intervalSeconds: %q is not a collector", collector))
+		}
+	}
+
+	return errors.Join(errs...)
+}